@@ -0,0 +1,129 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"strings"
+
+	"github.com/thinkofdeath/steven/format"
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/ui"
+	"github.com/thinkofdeath/steven/ui/scene"
+)
+
+// How long (in 1/60ths of a second) a toast is displayed
+// for including sliding in and out.
+const achievementToastTime = 180
+
+// achievementToasts displays a popup in the top right of the
+// screen when the player earns an achievement.
+type achievementToasts struct {
+	background *ui.Image
+	title      *ui.Text
+	name       *ui.Formatted
+
+	queue []format.AnyComponent
+	timer float64
+}
+
+func (a *achievementToasts) init(scene *scene.Type) {
+	tex := render.GetTexture("gui/achievement/achievement_background")
+	a.background = ui.NewImage(tex, 0, 0, 160*2, 32*2, 96/256.0, 202/256.0, 160/256.0, 32/256.0, 255, 255, 255).
+		Attach(ui.Top, ui.Right)
+	a.background.SetDraw(false)
+	scene.AddDrawable(a.background)
+
+	a.title = ui.NewText("Achievement get!", 16, 14, 255, 255, 0).Attach(ui.Top, ui.Left)
+	a.title.AttachTo(a.background)
+	a.title.SetDraw(false)
+	scene.AddDrawable(a.title)
+
+	a.name = ui.NewFormatted(format.Wrap(&format.TextComponent{}), 16, 34).Attach(ui.Top, ui.Left)
+	a.name.AttachTo(a.background)
+	a.name.SetDraw(false)
+	scene.AddDrawable(a.name)
+}
+
+// checkMessage queues a toast if the chat message announces
+// that this player earned an achievement.
+func (a *achievementToasts) checkMessage(msg format.AnyComponent) {
+	tc, ok := msg.Value.(*format.TranslateComponent)
+	if !ok || tc.Translate != "chat.type.achievement" || len(tc.With) != 2 {
+		return
+	}
+	if tc.With[0].String() != clientUsername.Value() {
+		return
+	}
+	name := tc.With[1]
+	// The announced name is wrapped in brackets for chat,
+	// prefer the achievement's name on its own
+	if inner, ok := findAchievementName(name); ok {
+		name = inner
+	}
+	a.queue = append(a.queue, name)
+}
+
+func findAchievementName(c format.AnyComponent) (format.AnyComponent, bool) {
+	var extra []format.AnyComponent
+	switch v := c.Value.(type) {
+	case *format.TranslateComponent:
+		if strings.HasPrefix(v.Translate, "achievement.") {
+			return c, true
+		}
+		extra = v.Extra
+	case *format.TextComponent:
+		extra = v.Extra
+	}
+	for _, e := range extra {
+		if f, ok := findAchievementName(e); ok {
+			return f, true
+		}
+	}
+	return c, false
+}
+
+func (a *achievementToasts) tick(delta float64) {
+	if a.timer <= 0 {
+		if len(a.queue) == 0 {
+			a.set(false)
+			return
+		}
+		a.name.Update(a.queue[0])
+		a.queue = a.queue[1:]
+		a.timer = achievementToastTime
+		a.set(true)
+	}
+	a.timer -= delta
+
+	// Slides in and out in the same way as vanilla
+	progress := 1 - a.timer/achievementToastTime
+	slide := progress * 2
+	if slide > 1 {
+		slide = 2 - slide
+	}
+	slide = 1 - slide*4
+	if slide < 0 {
+		slide = 0
+	}
+	slide *= slide
+	slide *= slide
+	a.background.SetY(-slide * 32 * 2)
+}
+
+func (a *achievementToasts) set(enabled bool) {
+	a.background.SetDraw(enabled)
+	a.title.SetDraw(enabled)
+	a.name.SetDraw(enabled)
+}
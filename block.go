@@ -47,6 +47,7 @@ type Block interface {
 	SID() uint16
 	Set(key string, val interface{}) Block
 	UpdateState(x, y, z int) Block
+	placementState(p blockPlacement) Block
 	states() []blockState
 
 	Collidable() bool
@@ -75,8 +76,9 @@ type Block interface {
 
 	CreateBlockEntity() BlockEntity
 
-	init(name string)
+	init(name string, tag reflect.StructTag)
 	toData() int
+	toSaveData() int
 }
 
 type blockState struct {
@@ -90,6 +92,8 @@ type baseBlock struct {
 	Parent        *BlockSet
 	Index         int
 	StevenID      uint16
+	LegacyData    int
+	SaveData      int
 	cullAgainst   bool
 	BlockVariants *blockVariants
 	translucent   bool
@@ -97,6 +101,7 @@ type baseBlock struct {
 	renderable    bool
 	bounds        []vmath.AABB
 	hardness      float64
+	lightEmitted  int
 }
 
 // Is returns whether this block is a member of the passed Set
@@ -108,7 +113,10 @@ func (b *baseBlock) BlockSet() *BlockSet {
 	return b.Parent
 }
 
-func (b *baseBlock) init(name string) {
+// init sets up the block with the properties shared by all block
+// types. Type specific properties are handled by the type's load
+// method.
+func (b *baseBlock) init(name string, tag reflect.StructTag) {
 	// plugin:name format
 	if strings.ContainsRune(name, ':') {
 		pos := strings.IndexRune(name, ':')
@@ -122,6 +130,12 @@ func (b *baseBlock) init(name string) {
 	b.collidable = true
 	b.renderable = true
 	b.hardness = 1.0
+	if hardness, err := strconv.ParseFloat(tag.Get("hardness"), 64); err == nil {
+		b.hardness = hardness
+	}
+	if light, err := strconv.Atoi(tag.Get("light")); err == nil {
+		b.lightEmitted = light
+	}
 }
 
 func (b *baseBlock) StepSound() (name string, vol, pitch float64)  { return "step.stone", 0.5, 1 }
@@ -152,6 +166,17 @@ func (b *baseBlock) SID() uint16 {
 	return b.StevenID
 }
 
+// toData returns the legacy data value of the block or -1 if the
+// block's state isn't saved by Minecraft.
+func (b *baseBlock) toData() int {
+	return b.LegacyData
+}
+
+// toSaveData returns the legacy data value to save the block as.
+func (b *baseBlock) toSaveData() int {
+	return b.SaveData
+}
+
 func (b *baseBlock) Collidable() bool {
 	return b.collidable
 }
@@ -188,7 +213,7 @@ func (b *baseBlock) LightReduction() int {
 }
 
 func (b *baseBlock) LightEmitted() int {
-	return 0
+	return b.lightEmitted
 }
 
 func (b *baseBlock) ShouldCullAgainst() bool {
@@ -219,6 +244,12 @@ func (b *baseBlock) UpdateState(x, y, z int) Block {
 	return b.Parent.Blocks[b.Index]
 }
 
+// placementState returns the state the block should be placed in
+// or nil if it can't be placed that way.
+func (b *baseBlock) placementState(p blockPlacement) Block {
+	return b.Parent.Blocks[b.Index]
+}
+
 func (b *baseBlock) Set(key string, val interface{}) Block {
 	index := 0
 	cur := reflect.ValueOf(b.Parent.Blocks[b.Index]).Elem()
@@ -300,6 +331,103 @@ type BlockSet struct {
 	Base   Block
 	Blocks []Block
 	states []state
+
+	dataRules []blockDataRule
+	// clientStates are the states the client works out from
+	// the neighbouring blocks which Minecraft doesn't save
+	clientStates map[string]bool
+}
+
+// blockDataRule describes how the states of a block map on to the
+// legacy data value. The rules are generated from blocks.json.
+type blockDataRule struct {
+	// If contains the states (formatted as strings) that must
+	// match for the rule to be used.
+	If map[string]string
+	// Data is the initial data value.
+	Data int
+	// Number is the name of a state whose integer value is added
+	// to the data.
+	Number string
+	// Values maps states to the bits they set in the data for each
+	// of their values.
+	Values map[string]map[string]int
+}
+
+// legacyData returns the data value of the block using the set's
+// rules or -1 if no rule matches.
+func (bs *BlockSet) legacyData(b Block) int {
+	states := map[string]interface{}{}
+	for _, s := range b.states() {
+		states[s.Key] = s.Value
+	}
+	value := func(key string) interface{} {
+		v, ok := states[key]
+		if !ok {
+			panic(fmt.Sprintf("unknown state %s for %s", key, b.Name()))
+		}
+		return v
+	}
+rules:
+	for _, r := range bs.dataRules {
+		for key, want := range r.If {
+			if fmt.Sprint(value(key)) != want {
+				continue rules
+			}
+		}
+		data := r.Data
+		if r.Number != "" {
+			v := reflect.ValueOf(value(r.Number))
+			switch v.Kind() {
+			case reflect.Int:
+				data += int(v.Int())
+			case reflect.Uint:
+				data += int(v.Uint())
+			default:
+				panic("invalid number state kind " + v.Kind().String())
+			}
+		}
+		for key, values := range r.Values {
+			data |= values[fmt.Sprint(value(key))]
+		}
+		return data
+	}
+	return -1
+}
+
+// saveData returns the legacy data value to save the block as.
+// States that the client works out itself aren't saved so they
+// are changed to the values the set's rules expect first, like
+// Minecraft they are worked out again once loaded.
+func (bs *BlockSet) saveData(b Block) int {
+	if data := bs.legacyData(b); data != -1 {
+		return data
+	}
+	for _, r := range bs.dataRules {
+		nb := b
+		for key, want := range r.If {
+			if bs.clientStates[key] {
+				nb = bs.withState(nb, key, want)
+			}
+		}
+		if data := bs.legacyData(nb); data != -1 {
+			return data
+		}
+	}
+	return -1
+}
+
+// withState returns the block with the state changed to the
+// value (formatted as a string).
+func (bs *BlockSet) withState(b Block, key, value string) Block {
+	for _, o := range bs.Blocks {
+		for _, s := range o.states() {
+			if s.Key == key && fmt.Sprint(s.Value) == value {
+				return b.Set(key, s.Value)
+			}
+		}
+	}
+	panic(fmt.Sprintf("unknown value %s for state %s of %s", value, key, b))
 }
 
 type state struct {
@@ -428,9 +556,9 @@ func (bs *BlockSet) stringify(block Block) string {
 	return buf.String()
 }
 
-func initBlocks() {
-	missingModel := findStateModel("steven", "missing_block")
-	// Flatten the ids
+// flattenBlocks gives every block state an id and works out
+// their legacy data values.
+func flattenBlocks() {
 	for _, bs := range blockSetsByID {
 		if bs == nil {
 			continue
@@ -443,10 +571,33 @@ func initBlocks() {
 			if len(allBlocks) > math.MaxUint16 {
 				panic("ran out of ids, time to do this correctly :(")
 			}
-			data := b.toData()
+			data := bs.legacyData(b)
+			br.FieldByName("LegacyData").SetInt(int64(data))
 			if data != -1 {
 				blocks[(bs.ID<<4)|data] = b
 			}
+		}
+	}
+	// Needs every state's index to be set first
+	for _, bs := range blockSetsByID {
+		if bs == nil {
+			continue
+		}
+		for _, b := range bs.Blocks {
+			reflect.ValueOf(b).Elem().FieldByName("SaveData").SetInt(int64(bs.saveData(b)))
+		}
+	}
+}
+
+func initBlocks() {
+	flattenBlocks()
+	missingModel := findStateModel("steven", "missing_block")
+	for _, bs := range blockSetsByID {
+		if bs == nil {
+			continue
+		}
+		for _, b := range bs.Blocks {
+			br := reflect.ValueOf(b).Elem()
 			// Liquids have custom rendering
 			if l, ok := b.(*blockLiquid); ok {
 				if l.Lava {
@@ -0,0 +1,204 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/type/direction"
+	"github.com/thinkofdeath/steven/type/vmath"
+)
+
+// Chest
+
+type blockChest struct {
+	baseBlock
+	Facing direction.Type `state:"facing,2-5"`
+}
+
+func (b *blockChest) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+	b.renderable = false
+}
+
+func (b *blockChest) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{
+			vmath.NewAABB(1/16.0, 0, 1/16.0, 15/16.0, 14/16.0, 15/16.0),
+		}
+	}
+	return b.bounds
+}
+
+func (b *blockChest) ModelVariant() string {
+	return fmt.Sprintf("facing=%s", b.Facing)
+}
+
+func (b *blockChest) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.Facing.Opposite())
+}
+
+func (b *blockChest) CreateBlockEntity() BlockEntity {
+	type chest struct {
+		blockComponent
+		chestComponent
+	}
+	c := &chest{}
+	c.facing = b.Facing
+	switch {
+	case b.Is(Blocks.TrappedChest):
+		c.texture = "entity/chest/trapped"
+	case b.Is(Blocks.EnderChest):
+		c.texture = "entity/chest/ender"
+		c.ender = true
+	default:
+		c.texture = "entity/chest/normal"
+	}
+	return c
+}
+
+// chestComponent draws the chest's model and animates
+// its lid when opened.
+//
+// TODO: Chests next to each other should be drawn as a single
+// double chest instead of two single ones.
+type chestComponent struct {
+	position Position
+	facing   direction.Type
+	texture  string
+	ender    bool
+	model    *render.Model
+
+	viewers           int
+	lidAngle, lastLid float64
+}
+
+// ChestComponent is implemented by chest block entities.
+type ChestComponent interface {
+	Viewers() int
+}
+
+func (c *chestComponent) Model() *render.Model { return c.model }
+func (c *chestComponent) Viewers() int         { return c.viewers }
+
+// BlockAction handles the lid action sent by the server which
+// contains the number of players currently viewing the chest.
+func (c *chestComponent) BlockAction(action, param byte) {
+	if action != 1 {
+		return
+	}
+	c.viewers = int(param)
+}
+
+func (c *chestComponent) create() {
+	tex := render.RelativeTexture(render.GetTexture(c.texture), 64, 64)
+
+	// Sub textures are laid out in the same way as vanilla's
+	// entity models.
+	box := func(verts []*render.ModelVertex, x, y, z, w, h, d float32, tx, ty int) []*render.ModelVertex {
+		iw, ih, id := int(w), int(h), int(d)
+		return appendBox(verts, x/16, y/16, z/16, w/16, h/16, d/16, [6]render.TextureInfo{
+			direction.North: tex.Sub(tx+id, ty+id, iw, ih),
+			direction.South: tex.Sub(tx+id+iw+id, ty+id, iw, ih),
+			direction.East:  tex.Sub(tx, ty+id, id, ih),
+			direction.West:  tex.Sub(tx+id+iw, ty+id, id, ih),
+			direction.Up:    tex.Sub(tx+id, ty, iw, id),
+			direction.Down:  tex.Sub(tx+id+iw, ty, iw, id),
+		})
+	}
+
+	// Centered on the block so it can be rotated
+	var base, lid []*render.ModelVertex
+	base = box(base, -7, 0, -7, 14, 10, 14, 0, 19)
+	lid = box(lid, -7, 9, -7, 14, 5, 14, 0, 0)
+	// Lock
+	lid = box(lid, -1, 7, -8, 2, 4, 1, 0, 0)
+
+	c.model = render.NewModel([][]*render.ModelVertex{
+		base,
+		lid,
+	})
+	c.model.Radius = 2
+	x, y, z := c.position.X, c.position.Y, c.position.Z
+	c.model.X, c.model.Y, c.model.Z = -float32(x)-0.5, -float32(y), float32(z)+0.5
+	c.updateMatrix()
+}
+
+func (c *chestComponent) updateMatrix() {
+	x, y, z := c.position.X, c.position.Y, c.position.Z
+	ang := float32(0)
+	switch c.facing {
+	case direction.South:
+		ang = math.Pi
+	case direction.East:
+		ang = math.Pi / 2
+	case direction.West:
+		ang = -math.Pi / 2
+	}
+	mat := mgl32.Translate3D(float32(x)+0.5, -float32(y), float32(z)+0.5).
+		Mul4(mgl32.Rotate3DY(ang).Mat4())
+	c.model.Matrix[0] = mat
+
+	// Ease the lid like vanilla does
+	open := 1 - c.lidAngle
+	open = 1 - open*open*open
+	// Hinged along the back of the chest
+	c.model.Matrix[1] = mat.
+		Mul4(mgl32.Translate3D(0, -9/16.0, 7/16.0)).
+		Mul4(mgl32.Rotate3DX(-float32(open) * (math.Pi / 2)).Mat4()).
+		Mul4(mgl32.Translate3D(0, 9/16.0, -7/16.0))
+}
+
+func (c *chestComponent) free() {
+	if c.model != nil {
+		c.model.Free()
+		c.model = nil
+	}
+}
+
+func esChestAdd(c *chestComponent, p BlockComponent) {
+	c.position = p.Position()
+	c.create()
+}
+
+func esChestRemove(c *chestComponent) {
+	c.free()
+}
+
+func esChestTick(c *chestComponent) {
+	c.lastLid = c.lidAngle
+	// 0.1 per a tick at 20 ticks a second
+	step := 0.1 * (Client.delta / 3)
+	if c.viewers > 0 {
+		c.lidAngle = math.Min(c.lidAngle+step, 1)
+	} else {
+		c.lidAngle = math.Max(c.lidAngle-step, 0)
+	}
+	if c.lidAngle == c.lastLid {
+		return
+	}
+
+	pos := c.position.Vec().Add(mgl32.Vec3{0.5, 0.5, 0.5})
+	if c.lastLid == 0 {
+		PlaySoundAt("random.chestopen", 0.5, 1, pos)
+	} else if c.lastLid >= 0.5 && c.lidAngle < 0.5 {
+		PlaySoundAt("random.chestclosed", 0.5, 1, pos)
+	}
+	c.updateMatrix()
+}
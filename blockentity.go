@@ -27,6 +27,14 @@ func init() {
 	addSystem(entitysys.Add, esSkullAdd)
 	addSystem(entitysys.Remove, esSkullRemove)
 	addSystem(entitysys.Add, esSignAdd)
+	addSystem(entitysys.Add, esChestAdd)
+	addSystem(entitysys.Remove, esChestRemove)
+	addSystem(entitysys.Tick, esChestTick)
+	addSystem(entitysys.Add, esPistonAdd)
+	addSystem(entitysys.Add, esNoteBlockAdd)
+	addSystem(entitysys.Add, esMovingBlockAdd)
+	addSystem(entitysys.Remove, esMovingBlockRemove)
+	addSystem(entitysys.Tick, esMovingBlockTick)
 	addSystem(entitysys.Tick, lightBlockModel)
 }
 
@@ -74,6 +82,12 @@ type BlockNBTComponent interface {
 	CanHandleAction(action int) bool
 }
 
+// BlockActionComponent is implemented by block entities that
+// react to block actions sent by the server.
+type BlockActionComponent interface {
+	BlockAction(action, param byte)
+}
+
 type blockBreakComponent struct {
 	blockComponent
 	stage int
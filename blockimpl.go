@@ -88,11 +88,6 @@ func (b *blockStone) NameLocaleKey() string {
 	return "unknown"
 }
 
-func (b *blockStone) toData() int {
-	data := int(b.Variant)
-	return data
-}
-
 // Grass
 
 type blockGrass struct {
@@ -125,13 +120,6 @@ func (g *blockGrass) TintImage() *image.NRGBA {
 	return grassBiomeColors
 }
 
-func (g *blockGrass) toData() int {
-	if g.Snowy {
-		return -1
-	}
-	return 0
-}
-
 // Tall grass
 
 type tallGrassType int
@@ -172,10 +160,6 @@ func (b *blockTallGrass) TintImage() *image.NRGBA {
 	return grassBiomeColors
 }
 
-func (b *blockTallGrass) toData() int {
-	return int(b.Type)
-}
-
 // Bed
 
 type bedPart int
@@ -219,27 +203,6 @@ func (b *blockBed) ModelVariant() string {
 	return fmt.Sprintf("facing=%s,part=%s", b.Facing, b.Part)
 }
 
-func (b *blockBed) toData() int {
-	data := 0
-	switch b.Facing {
-	case direction.South:
-		data = 0
-	case direction.West:
-		data = 1
-	case direction.North:
-		data = 2
-	case direction.East:
-		data = 3
-	}
-	if b.Occupied {
-		data |= 0x4
-	}
-	if b.Part == bedHead {
-		data |= 0x8
-	}
-	return data
-}
-
 // Sponge
 
 type blockSponge struct {
@@ -251,14 +214,6 @@ func (b *blockSponge) ModelVariant() string {
 	return fmt.Sprintf("wet=%t", b.Wet)
 }
 
-func (b *blockSponge) toData() int {
-	data := 0
-	if b.Wet {
-		data = 1
-	}
-	return data
-}
-
 // Door
 
 type doorHalf int
@@ -312,6 +267,19 @@ func (b *blockDoor) ModelVariant() string {
 	return fmt.Sprintf("facing=%s,half=%s,hinge=%s,open=%t", b.Facing, b.Half, b.Hinge, b.Open)
 }
 
+// placementState returns the lower half of the door, the upper
+// half is placed above it separately.
+func (b *blockDoor) placementState(p blockPlacement) Block {
+	if p.Face != direction.Up {
+		return nil
+	}
+	return b.Set("facing", p.Facing).
+		Set("half", doorLower).
+		Set("hinge", doorLeft).
+		Set("open", false).
+		Set("powered", false)
+}
+
 func (b *blockDoor) UpdateState(x, y, z int) Block {
 	if b.Half == doorUpper {
 		o := chunkMap.Block(x, y-1, z)
@@ -329,34 +297,6 @@ func (b *blockDoor) UpdateState(x, y, z int) Block {
 	return b
 }
 
-func (b *blockDoor) toData() int {
-	data := 0
-	if b.Half == doorUpper {
-		data |= 0x8
-		if b.Hinge == doorRight {
-			data |= 0x1
-		}
-		if b.Powered {
-			data |= 0x2
-		}
-	} else {
-		switch b.Facing {
-		case direction.East:
-			data = 0
-		case direction.South:
-			data = 1
-		case direction.West:
-			data = 2
-		case direction.North:
-			data = 3
-		}
-		if b.Open {
-			data |= 0x4
-		}
-	}
-	return data
-}
-
 // Dispenser
 
 type blockDispenser struct {
@@ -369,26 +309,8 @@ func (b *blockDispenser) ModelVariant() string {
 	return fmt.Sprintf("facing=%s", b.Facing)
 }
 
-func (b *blockDispenser) toData() int {
-	data := 0
-	switch b.Facing {
-	case direction.Down:
-		data = 0
-	case direction.Up:
-		data = 1
-	case direction.North:
-		data = 2
-	case direction.South:
-		data = 3
-	case direction.West:
-		data = 4
-	case direction.East:
-		data = 5
-	}
-	if b.Triggered {
-		data |= 0x8
-	}
-	return data
+func (b *blockDispenser) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.lookingAt()).Set("triggered", false)
 }
 
 // Powered rail
@@ -457,14 +379,6 @@ func (b *blockPoweredRail) ModelVariant() string {
 	return fmt.Sprintf("powered=%t,shape=%s", b.Powered, b.Shape)
 }
 
-func (b *blockPoweredRail) toData() int {
-	data := int(b.Shape)
-	if b.Powered {
-		data |= 0x8
-	}
-	return data
-}
-
 // Rail
 
 type blockRail struct {
@@ -489,10 +403,6 @@ func (b *blockRail) ModelVariant() string {
 	return fmt.Sprintf("shape=%s", b.Shape)
 }
 
-func (b *blockRail) toData() int {
-	return int(b.Shape)
-}
-
 // Dead bush
 
 type blockDeadBush struct {
@@ -508,10 +418,6 @@ func (b *blockDeadBush) ModelName() string {
 	return "dead_bush"
 }
 
-func (b *blockDeadBush) toData() int {
-	return 0
-}
-
 // Fence
 
 type blockFence struct {
@@ -547,13 +453,6 @@ func (b *blockFence) UpdateState(x, y, z int) Block {
 	return block
 }
 
-func (b *blockFence) toData() int {
-	if !b.North && !b.South && !b.East && !b.West {
-		return 0
-	}
-	return -1
-}
-
 // Fence Gate
 
 type blockFenceGate struct {
@@ -585,25 +484,8 @@ func (b *blockFenceGate) ModelVariant() string {
 	return fmt.Sprintf("facing=%s,in_wall=%t,open=%t", b.Facing, b.InWall, b.Open)
 }
 
-func (b *blockFenceGate) toData() int {
-	if b.Powered || b.InWall {
-		return -1
-	}
-	data := 0
-	switch b.Facing {
-	case direction.South:
-		data = 0
-	case direction.West:
-		data = 1
-	case direction.North:
-		data = 2
-	case direction.East:
-		data = 3
-	}
-	if b.Open {
-		data |= 0x4
-	}
-	return data
+func (b *blockFenceGate) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.Facing).Set("open", false)
 }
 
 // Wall
@@ -672,13 +554,6 @@ func (b *blockWall) ModelName() string {
 	return b.Variant.String() + "_wall"
 }
 
-func (b *blockWall) toData() int {
-	if !b.North && !b.South && !b.East && !b.West && !b.Up {
-		return int(b.Variant)
-	}
-	return -1
-}
-
 // Stained glass
 
 type color int
@@ -754,10 +629,6 @@ func (b *blockStainedGlass) ModelName() string {
 	return b.Color.String() + "_stained_glass"
 }
 
-func (b *blockStainedGlass) toData() int {
-	return int(b.Color)
-}
-
 // Connectable
 
 type blockConnectable struct {
@@ -820,13 +691,6 @@ func (b *blockConnectable) ModelVariant() string {
 	return fmt.Sprintf("east=%t,north=%t,south=%t,west=%t", b.East, b.North, b.South, b.West)
 }
 
-func (b *blockConnectable) toData() int {
-	if !b.North && !b.South && !b.East && !b.West {
-		return 0
-	}
-	return -1
-}
-
 // Stained Glass Pane
 
 type blockStainedGlassPane struct {
@@ -895,13 +759,6 @@ func (b *blockStainedGlassPane) UpdateState(x, y, z int) Block {
 	return block
 }
 
-func (b *blockStainedGlassPane) toData() int {
-	if !b.North && !b.South && !b.East && !b.West {
-		return int(b.Color)
-	}
-	return -1
-}
-
 // Stairs
 
 type stairHalf int
@@ -1016,6 +873,14 @@ func (b *blockStairs) ModelVariant() string {
 	return fmt.Sprintf("facing=%s,half=%s,shape=%s", b.Facing, b.Half, b.Shape)
 }
 
+func (b *blockStairs) placementState(p blockPlacement) Block {
+	half := shBottom
+	if p.topHalf() {
+		half = shTop
+	}
+	return b.Set("facing", p.Facing).Set("half", half)
+}
+
 func (b *blockStairs) UpdateState(x, y, z int) Block {
 	// Facing is the side of the back of the stairs
 	// If the stair in front of the back doesn't have the
@@ -1052,27 +917,6 @@ func (b *blockStairs) UpdateState(x, y, z int) Block {
 	return b
 }
 
-func (b *blockStairs) toData() int {
-	if b.Shape != ssStraight {
-		return -1
-	}
-	data := 0
-	switch b.Facing {
-	case direction.East:
-		data = 0
-	case direction.West:
-		data = 1
-	case direction.South:
-		data = 2
-	case direction.North:
-		data = 3
-	}
-	if b.Half == shTop {
-		data |= 0x4
-	}
-	return data
-}
-
 // Vines
 
 type blockVines struct {
@@ -1103,23 +947,6 @@ func (b *blockVines) TintImage() *image.NRGBA {
 	return foliageBiomeColors
 }
 
-func (b *blockVines) toData() int {
-	data := 0
-	if b.South {
-		data |= 0x1
-	}
-	if b.West {
-		data |= 0x2
-	}
-	if b.North {
-		data |= 0x4
-	}
-	if b.East {
-		data |= 0x8
-	}
-	return data
-}
-
 // Stained clay
 
 type blockStainedClay struct {
@@ -1131,10 +958,6 @@ func (b *blockStainedClay) ModelName() string {
 	return b.Color.String() + "_stained_hardened_clay"
 }
 
-func (b *blockStainedClay) toData() int {
-	return int(b.Color)
-}
-
 // Wool
 
 type blockWool struct {
@@ -1146,10 +969,6 @@ func (b *blockWool) ModelName() string {
 	return b.Color.String() + "_wool"
 }
 
-func (b *blockWool) toData() int {
-	return int(b.Color)
-}
-
 // Piston
 
 type blockPiston struct {
@@ -1194,26 +1013,18 @@ func (b *blockPiston) ModelVariant() string {
 	return fmt.Sprintf("extended=%t,facing=%s", b.Extended, b.Facing)
 }
 
-func (b *blockPiston) toData() int {
-	data := 0
-	switch b.Facing {
-	case direction.Down:
-		data = 0
-	case direction.Up:
-		data = 1
-	case direction.North:
-		data = 2
-	case direction.South:
-		data = 3
-	case direction.West:
-		data = 4
-	case direction.East:
-		data = 5
-	}
-	if b.Extended {
-		data |= 0x8
+func (b *blockPiston) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.lookingAt()).Set("extended", false)
+}
+
+func (b *blockPiston) CreateBlockEntity() BlockEntity {
+	type piston struct {
+		blockComponent
+		pistonComponent
 	}
-	return data
+	p := &piston{}
+	p.sticky = b.Is(Blocks.StickyPiston)
+	return p
 }
 
 type pistonType int
@@ -1280,31 +1091,6 @@ func (b *blockPistonHead) ModelVariant() string {
 	return fmt.Sprintf("facing=%s,short=%t,type=%s", b.Facing, b.Short, b.Type)
 }
 
-func (b *blockPistonHead) toData() int {
-	if b.Short {
-		return -1
-	}
-	data := 0
-	switch b.Facing {
-	case direction.Down:
-		data = 0
-	case direction.Up:
-		data = 1
-	case direction.North:
-		data = 2
-	case direction.South:
-		data = 3
-	case direction.West:
-		data = 4
-	case direction.East:
-		data = 5
-	}
-	if b.Type == ptSticky {
-		data |= 0x8
-	}
-	return data
-}
-
 // Slabs
 
 type slabHalf int
@@ -1425,24 +1211,16 @@ func (b *blockSlab) ModelVariant() string {
 	return fmt.Sprintf("half=%s", b.Half)
 }
 
-func (b *blockSlab) ModelName() string {
-	return fmt.Sprintf("%s_slab", b.Variant)
+func (b *blockSlab) placementState(p blockPlacement) Block {
+	half := slabBottom
+	if p.topHalf() {
+		half = slabTop
+	}
+	return b.Set("half", half)
 }
 
-func (b *blockSlab) toData() int {
-	data := 0
-	switch b.Type {
-	case "stone":
-		data = int(b.Variant)
-	case "stone2":
-		data = int(b.Variant - 8)
-	case "wood":
-		data = int(b.Variant - 9)
-	}
-	if b.Half == slabTop {
-		data |= 0x8
-	}
-	return data
+func (b *blockSlab) ModelName() string {
+	return fmt.Sprintf("%s_slab", b.Variant)
 }
 
 type blockSlabDouble struct {
@@ -1471,19 +1249,6 @@ func (b *blockSlabDouble) ModelName() string {
 	return fmt.Sprintf("%s_double_slab", b.Variant)
 }
 
-func (b *blockSlabDouble) toData() int {
-	data := 0
-	switch b.Type {
-	case "stone":
-		data = int(b.Variant)
-	case "stone2":
-		data = int(b.Variant - 8)
-	case "wood":
-		data = int(b.Variant - 9)
-	}
-	return data
-}
-
 type blockSlabDoubleSeamless struct {
 	baseBlock
 	Seamless bool        `state:"seamless"`
@@ -1520,22 +1285,6 @@ func (b *blockSlabDoubleSeamless) ModelName() string {
 	return fmt.Sprintf("%s_double_slab", b.Variant)
 }
 
-func (b *blockSlabDoubleSeamless) toData() int {
-	data := 0
-	switch b.Type {
-	case "stone":
-		data = int(b.Variant)
-	case "stone2":
-		data = int(b.Variant - 8)
-	case "wood":
-		data = int(b.Variant - 9)
-	}
-	if b.Seamless {
-		data |= 0x8
-	}
-	return data
-}
-
 // Carpet
 
 type blockCarpet struct {
@@ -1560,10 +1309,6 @@ func (b *blockCarpet) ModelName() string {
 	return b.Color.String() + "_carpet"
 }
 
-func (b *blockCarpet) toData() int {
-	return int(b.Color)
-}
-
 // Torch
 
 type blockTorch struct {
@@ -1578,10 +1323,6 @@ func (b *blockTorch) load(tag reflect.StructTag) {
 	b.collidable = false
 }
 
-func (b *blockTorch) LightEmitted() int {
-	return 13
-}
-
 func (b *blockTorch) ModelName() string {
 	return b.Model
 }
@@ -1591,6 +1332,22 @@ func (b *blockTorch) ModelVariant() string {
 	return fmt.Sprintf("facing=%s", facing)
 }
 
+func (b *blockTorch) placementState(p blockPlacement) Block {
+	switch p.Face {
+	case direction.East:
+		return b.Set("facing", 0)
+	case direction.West:
+		return b.Set("facing", 1)
+	case direction.South:
+		return b.Set("facing", 2)
+	case direction.North:
+		return b.Set("facing", 3)
+	case direction.Up:
+		return b.Set("facing", 4)
+	}
+	return nil
+}
+
 func (b *blockTorch) facing() direction.Type {
 	switch b.Facing {
 	case 0:
@@ -1607,22 +1364,6 @@ func (b *blockTorch) facing() direction.Type {
 	return direction.Invalid
 }
 
-func (b *blockTorch) toData() int {
-	switch b.facing() {
-	case direction.East:
-		return 1
-	case direction.West:
-		return 2
-	case direction.South:
-		return 3
-	case direction.North:
-		return 4
-	case direction.Up:
-		return 5
-	}
-	return -1
-}
-
 // Wall Sign
 
 type blockWallSign struct {
@@ -1676,10 +1417,6 @@ func (b *blockWallSign) CreateBlockEntity() BlockEntity {
 	return w
 }
 
-func (b *blockWallSign) toData() int {
-	return int(b.Facing)
-}
-
 // Floor Sign
 
 type blockFloorSign struct {
@@ -1717,10 +1454,6 @@ func (b *blockFloorSign) CreateBlockEntity() BlockEntity {
 	return w
 }
 
-func (b *blockFloorSign) toData() int {
-	return b.Rotation
-}
-
 // Skull
 
 type blockSkull struct {
@@ -1767,26 +1500,6 @@ func (b *blockSkull) CollisionBounds() []vmath.AABB {
 	return b.bounds
 }
 
-func (b *blockSkull) toData() int {
-	data := 0
-	switch b.Facing {
-	case direction.Up:
-		data = 1
-	case direction.North:
-		data = 2
-	case direction.South:
-		data = 3
-	case direction.East:
-		data = 4
-	case direction.West:
-		data = 5
-	}
-	if b.NoDrop {
-		data |= 0x8
-	}
-	return data
-}
-
 // Portal
 
 type blockPortal struct {
@@ -1816,16 +1529,6 @@ func (b *blockPortal) ModelVariant() string {
 	return fmt.Sprintf("axis=%s", b.Axis)
 }
 
-func (b *blockPortal) toData() int {
-	switch b.Axis {
-	case axisX:
-		return 1
-	case axisZ:
-		return 2
-	}
-	return 0
-}
-
 // Lilypad
 
 type blockLilypad struct {
@@ -1849,10 +1552,6 @@ func (b *blockLilypad) TintImage() *image.NRGBA {
 	return foliageBiomeColors
 }
 
-func (b *blockLilypad) toData() int {
-	return 0
-}
-
 // Stone brick
 
 type stoneBrickVariant int
@@ -1887,11 +1586,6 @@ func (b *blockStoneBrick) ModelName() string {
 	return b.Variant.String()
 }
 
-func (b *blockStoneBrick) toData() int {
-	data := int(b.Variant)
-	return data
-}
-
 // Yellow flower
 
 type blockYellowFlower struct {
@@ -1907,10 +1601,6 @@ func (b *blockYellowFlower) ModelName() string {
 	return "dandelion"
 }
 
-func (b *blockYellowFlower) toData() int {
-	return 0
-}
-
 // Red flower
 
 type redFlowerVariant int
@@ -1965,10 +1655,6 @@ func (b *blockRedFlower) ModelName() string {
 	return b.Variant.String()
 }
 
-func (b *blockRedFlower) toData() int {
-	return int(b.Variant)
-}
-
 // Fire
 
 var burnableBlocks map[*BlockSet]bool
@@ -2057,10 +1743,6 @@ func (b *blockFire) UpdateState(x, y, z int) Block {
 	return Blocks.Fire.Base.Set("age", b.Age)
 }
 
-func (b *blockFire) toData() int {
-	return b.Age
-}
-
 // Redstone
 
 type redstoneConnection int
@@ -2137,10 +1819,6 @@ func (b *blockRedstone) CollisionBounds() []vmath.AABB {
 	return b.bounds
 }
 
-func (b *blockRedstone) toData() int {
-	return b.Power
-}
-
 // Cactus
 
 type blockCactus struct {
@@ -2162,10 +1840,6 @@ func (b *blockCactus) CollisionBounds() []vmath.AABB {
 	return b.bounds
 }
 
-func (b *blockCactus) toData() int {
-	return b.Age
-}
-
 // Crop
 
 type blockCrop struct {
@@ -2183,10 +1857,6 @@ func (b *blockCrop) ModelVariant() string {
 	return fmt.Sprintf("age=%d", b.Age)
 }
 
-func (b *blockCrop) toData() int {
-	return b.Age
-}
-
 // Farmland
 
 type blockFarmland struct {
@@ -2212,8 +1882,21 @@ func (b *blockFarmland) ModelVariant() string {
 	return fmt.Sprintf("moisture=%d", b.Moisture)
 }
 
-func (b *blockFarmland) toData() int {
-	return b.Moisture
+// Soul sand
+
+type blockSoulSand struct {
+	baseBlock
+}
+
+// The player sinks slightly into soul sand which slows them
+// down.
+func (b *blockSoulSand) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{
+			vmath.NewAABB(0.0, 0.0, 0.0, 1.0, 14.0/16.0, 1.0),
+		}
+	}
+	return b.bounds
 }
 
 // Quartz block
@@ -2264,6 +1947,21 @@ func (b *blockQuartzBlock) ModelVariant() string {
 	return fmt.Sprintf("axis=%s", a)
 }
 
+// placementState lines the pillar variants up with the face they
+// were placed against.
+func (b *blockQuartzBlock) placementState(p blockPlacement) Block {
+	if b.Variant != qvLinesX && b.Variant != qvLinesY && b.Variant != qvLinesZ {
+		return b
+	}
+	switch p.Face {
+	case direction.East, direction.West:
+		return b.Set("variant", qvLinesX)
+	case direction.North, direction.South:
+		return b.Set("variant", qvLinesZ)
+	}
+	return b.Set("variant", qvLinesY)
+}
+
 func (b *blockQuartzBlock) ModelName() string {
 	switch b.Variant {
 	case qvLinesX, qvLinesY, qvLinesZ:
@@ -2276,22 +1974,6 @@ func (b *blockQuartzBlock) ModelName() string {
 	panic("unknown quartz block")
 }
 
-func (b *blockQuartzBlock) toData() int {
-	switch b.Variant {
-	case qvLinesX:
-		return 3
-	case qvLinesY:
-		return 2
-	case qvLinesZ:
-		return 4
-	case qvDefault:
-		return 0
-	case qvChiseled:
-		return 1
-	}
-	return -1
-}
-
 // Snow layer
 
 type blockSnowLayer struct {
@@ -2317,10 +1999,6 @@ func (b *blockSnowLayer) ModelVariant() string {
 	return fmt.Sprintf("layers=%d", b.Layers)
 }
 
-func (b *blockSnowLayer) toData() int {
-	return b.Layers - 1
-}
-
 // Double plant
 
 type doublePlantVariant int
@@ -2409,15 +2087,356 @@ func (b *blockDoublePlant) TintImage() *image.NRGBA {
 	return grassBiomeColors
 }
 
-func (b *blockDoublePlant) toData() int {
-	if b.Half == dpUpper {
-		if b.Variant != dpvSunflower {
-			return -1
+// Trap door
+
+type trapDoorHalf int
+
+const (
+	tdTop trapDoorHalf = iota
+	tdBottom
+)
+
+func (t trapDoorHalf) String() string {
+	switch t {
+	case tdTop:
+		return "top"
+	case tdBottom:
+		return "bottom"
+	}
+	return fmt.Sprintf("trapDoorHalf(%d)", t)
+}
+
+type blockTrapDoor struct {
+	baseBlock
+	Facing direction.Type `state:"facing,2-5"`
+	Half   trapDoorHalf   `state:"half,0-1"`
+	Open   bool           `state:"open"`
+}
+
+func (b *blockTrapDoor) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockTrapDoor) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		bb := vmath.NewAABB(0, 0, 0, 1.0, 3/16.0, 1.0)
+		if b.Open {
+			switch b.Facing {
+			case direction.North:
+				bb = vmath.NewAABB(0, 0, 13/16.0, 1.0, 1.0, 1.0)
+			case direction.South:
+				bb = vmath.NewAABB(0, 0, 0, 1.0, 1.0, 3/16.0)
+			case direction.West:
+				bb = vmath.NewAABB(13/16.0, 0, 0, 1.0, 1.0, 1.0)
+			case direction.East:
+				bb = vmath.NewAABB(0, 0, 0, 3/16.0, 1.0, 1.0)
+			}
+		} else if b.Half == tdTop {
+			bb = bb.Shift(0, 13/16.0, 0)
+		}
+		b.bounds = []vmath.AABB{bb}
+	}
+	return b.bounds
+}
+
+func (b *blockTrapDoor) ModelVariant() string {
+	return fmt.Sprintf("facing=%s,half=%s,open=%t", b.Facing, b.Half, b.Open)
+}
+
+func (b *blockTrapDoor) placementState(p blockPlacement) Block {
+	var block Block = b.Set("open", false)
+	switch p.Face {
+	case direction.Up:
+		return block.Set("facing", p.Facing.Opposite()).Set("half", tdBottom)
+	case direction.Down:
+		return block.Set("facing", p.Facing.Opposite()).Set("half", tdTop)
+	}
+	half := tdBottom
+	if p.Cursor.Y() > 0.5 {
+		half = tdTop
+	}
+	return block.Set("facing", p.Face).Set("half", half)
+}
+
+// Ladder
+
+type blockLadder struct {
+	baseBlock
+	Facing direction.Type `state:"facing,2-5"`
+}
+
+func (b *blockLadder) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockLadder) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		var bb vmath.AABB
+		switch b.Facing {
+		case direction.North:
+			bb = vmath.NewAABB(0, 0, 13/16.0, 1.0, 1.0, 1.0)
+		case direction.South:
+			bb = vmath.NewAABB(0, 0, 0, 1.0, 1.0, 3/16.0)
+		case direction.West:
+			bb = vmath.NewAABB(13/16.0, 0, 0, 1.0, 1.0, 1.0)
+		case direction.East:
+			bb = vmath.NewAABB(0, 0, 0, 3/16.0, 1.0, 1.0)
+		}
+		b.bounds = []vmath.AABB{bb}
+	}
+	return b.bounds
+}
+
+func (b *blockLadder) ModelVariant() string {
+	return fmt.Sprintf("facing=%s", b.Facing)
+}
+
+func (b *blockLadder) placementState(p blockPlacement) Block {
+	if p.Face == direction.Up || p.Face == direction.Down {
+		return nil
+	}
+	return b.Set("facing", p.Face)
+}
+
+// Anvil
+
+type blockAnvil struct {
+	baseBlock
+	Facing direction.Type `state:"facing,2-5"`
+	Damage int            `state:"damage,0-2"`
+}
+
+func (b *blockAnvil) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockAnvil) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		bb := vmath.NewAABB(2/16.0, 0, 0, 14/16.0, 1.0, 1.0)
+		if b.Facing == direction.East || b.Facing == direction.West {
+			bb = vmath.NewAABB(0, 0, 2/16.0, 1.0, 1.0, 14/16.0)
+		}
+		b.bounds = []vmath.AABB{bb}
+	}
+	return b.bounds
+}
+
+func (b *blockAnvil) ModelVariant() string {
+	return fmt.Sprintf("damage=%d,facing=%s", b.Damage, b.Facing)
+}
+
+func (b *blockAnvil) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.Facing.Clockwise())
+}
+
+// Cauldron
+
+type blockCauldron struct {
+	baseBlock
+	Level int `state:"level,0-3"`
+}
+
+func (b *blockCauldron) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockCauldron) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = containerBounds(5 / 16.0)
+	}
+	return b.bounds
+}
+
+func (b *blockCauldron) ModelVariant() string {
+	return fmt.Sprintf("level=%d", b.Level)
+}
+
+// containerBounds returns the bounds of a block with a floor of the
+// passed height and thin walls on each side like cauldrons and
+// hoppers.
+func containerBounds(floor float32) []vmath.AABB {
+	return []vmath.AABB{
+		vmath.NewAABB(0, 0, 0, 1.0, floor, 1.0),
+		vmath.NewAABB(0, 0, 0, 1.0, 1.0, 2/16.0),
+		vmath.NewAABB(0, 0, 14/16.0, 1.0, 1.0, 1.0),
+		vmath.NewAABB(0, 0, 0, 2/16.0, 1.0, 1.0),
+		vmath.NewAABB(14/16.0, 0, 0, 1.0, 1.0, 1.0),
+	}
+}
+
+// Brewing stand
+
+type blockBrewingStand struct {
+	baseBlock
+	HasBottle0 bool `state:"has_bottle_0"`
+	HasBottle1 bool `state:"has_bottle_1"`
+	HasBottle2 bool `state:"has_bottle_2"`
+}
+
+func (b *blockBrewingStand) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockBrewingStand) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{
+			vmath.NewAABB(0, 0, 0, 1.0, 2/16.0, 1.0),
+			vmath.NewAABB(7/16.0, 0, 7/16.0, 9/16.0, 14/16.0, 9/16.0),
+		}
+	}
+	return b.bounds
+}
+
+func (b *blockBrewingStand) ModelVariant() string {
+	return fmt.Sprintf("has_bottle_0=%t,has_bottle_1=%t,has_bottle_2=%t",
+		b.HasBottle0, b.HasBottle1, b.HasBottle2)
+}
+
+// Hopper
+
+type blockHopper struct {
+	baseBlock
+	// Hoppers can't face upwards
+	Facing direction.Type `state:"facing,1-5"`
+	// Hoppers are disabled whilst powered by redstone
+	Enabled bool `state:"enabled"`
+}
+
+func (b *blockHopper) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockHopper) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = containerBounds(10 / 16.0)
+	}
+	return b.bounds
+}
+
+func (b *blockHopper) ModelVariant() string {
+	// The model doesn't change when disabled
+	return fmt.Sprintf("facing=%s", b.Facing)
+}
+
+func (b *blockHopper) placementState(p blockPlacement) Block {
+	facing := p.Face.Opposite()
+	if facing == direction.Up {
+		facing = direction.Down
+	}
+	return b.Set("facing", facing).Set("enabled", true)
+}
+
+// Enchanting table
+
+type blockEnchantingTable struct {
+	baseBlock
+}
+
+func (b *blockEnchantingTable) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockEnchantingTable) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{
+			vmath.NewAABB(0, 0, 0, 1.0, 12/16.0, 1.0),
+		}
+	}
+	return b.bounds
+}
+
+// End portal frame
+
+type blockEndPortalFrame struct {
+	baseBlock
+	Facing direction.Type `state:"facing,2-5"`
+	Eye    bool           `state:"eye"`
+}
+
+func (b *blockEndPortalFrame) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockEndPortalFrame) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{
+			vmath.NewAABB(0, 0, 0, 1.0, 13/16.0, 1.0),
+		}
+		if b.Eye {
+			b.bounds = append(b.bounds, vmath.NewAABB(5/16.0, 13/16.0, 5/16.0, 11/16.0, 1.0, 11/16.0))
+		}
+	}
+	return b.bounds
+}
+
+func (b *blockEndPortalFrame) ModelVariant() string {
+	return fmt.Sprintf("eye=%t,facing=%s", b.Eye, b.Facing)
+}
+
+func (b *blockEndPortalFrame) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.Facing.Opposite()).Set("eye", false)
+}
+
+// Cocoa
+
+type blockCocoa struct {
+	baseBlock
+	// Facing is the side the log the pod grows on is
+	Facing direction.Type `state:"facing,2-5"`
+	Age    int            `state:"age,0-2"`
+}
+
+func (b *blockCocoa) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockCocoa) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		width := float32(4 + b.Age*2)
+		height := float32(5 + b.Age*2)
+		half := width / 2
+		bottom := (12 - height) / 16
+		var bb vmath.AABB
+		switch b.Facing {
+		case direction.South:
+			bb = vmath.NewAABB((8-half)/16, bottom, (15-width)/16, (8+half)/16, 0.75, 15/16.0)
+		case direction.North:
+			bb = vmath.NewAABB((8-half)/16, bottom, 1/16.0, (8+half)/16, 0.75, (1+width)/16)
+		case direction.West:
+			bb = vmath.NewAABB(1/16.0, bottom, (8-half)/16, (1+width)/16, 0.75, (8+half)/16)
+		case direction.East:
+			bb = vmath.NewAABB((15-width)/16, bottom, (8-half)/16, 15/16.0, 0.75, (8+half)/16)
 		}
-		return 8 | int(b.Facing)
+		b.bounds = []vmath.AABB{bb}
 	}
-	if b.Facing != direction.East {
-		return -1
+	return b.bounds
+}
+
+func (b *blockCocoa) ModelVariant() string {
+	return fmt.Sprintf("age=%d,facing=%s", b.Age, b.Facing)
+}
+
+// Nether wart
+
+type blockNetherWart struct {
+	baseBlock
+	Age int `state:"age,0-3"`
+}
+
+func (b *blockNetherWart) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+	b.collidable = false
+}
+
+func (b *blockNetherWart) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{
+			vmath.NewAABB(0, 0, 0, 1.0, 4/16.0, 1.0),
+		}
 	}
-	return int(b.Variant)
+	return b.bounds
+}
+
+func (b *blockNetherWart) ModelVariant() string {
+	return fmt.Sprintf("age=%d", b.Age)
 }
@@ -54,10 +54,6 @@ func (l *blockLiquid) LightEmitted() int {
 	return 0
 }
 
-func (l *blockLiquid) toData() int {
-	return l.Level
-}
-
 func (l *blockLiquid) renderLiquid(bs *blocksSnapshot, x, y, z int, buf *builder.Buffer, indices *int) {
 	tex := l.Tex
 	var b1, b2 *BlockSet
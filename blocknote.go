@@ -0,0 +1,68 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Note block
+
+type blockNoteBlock struct {
+	baseBlock
+}
+
+func (b *blockNoteBlock) CreateBlockEntity() BlockEntity {
+	type noteBlock struct {
+		blockComponent
+		noteBlockComponent
+	}
+	return &noteBlock{}
+}
+
+// The sounds for each instrument in the order the server
+// uses for them.
+var noteInstruments = []string{
+	"note.harp",
+	"note.bd",
+	"note.snare",
+	"note.hat",
+	"note.bassattack",
+}
+
+// noteBlockComponent plays the block's note when told to by
+// the server.
+type noteBlockComponent struct {
+	position Position
+}
+
+func (n *noteBlockComponent) BlockAction(instrument, note byte) {
+	if int(instrument) >= len(noteInstruments) {
+		return
+	}
+	// Notes span two octaves centered on the sound's
+	// normal pitch
+	pitch := math.Pow(2, float64(int(note)-12)/12)
+	PlaySoundAt(
+		noteInstruments[instrument], 3, pitch,
+		n.position.Vec().Add(mgl32.Vec3{0.5, 0.5, 0.5}),
+	)
+}
+
+func esNoteBlockAdd(n *noteBlockComponent, p BlockComponent) {
+	n.position = p.Position()
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/type/direction"
+)
+
+// The max number of blocks a piston can push
+const pistonPushLimit = 12
+
+// pistonComponent handles the extend and retract actions
+// for pistons.
+type pistonComponent struct {
+	position Position
+	sticky   bool
+}
+
+func (p *pistonComponent) BlockAction(action, param byte) {
+	dir := directionFromProtocol(param)
+	head := Blocks.PistonHead.Base.
+		Set("facing", dir)
+	if p.sticky {
+		head = head.Set("type", ptSticky)
+	}
+	headPos := p.position.ShiftDir(dir)
+	sndPos := p.position.Vec().Add(mgl32.Vec3{0.5, 0.5, 0.5})
+
+	switch action {
+	case 0: // Extend
+		// Push the blocks in front along with the head
+		pos := headPos
+		for i := 0; i < pistonPushLimit; i++ {
+			b := chunkMap.Block(pos.X, pos.Y, pos.Z)
+			if b.Is(Blocks.Air) || !b.Renderable() {
+				break
+			}
+			addMovingBlock(b, pos.ShiftDir(dir), dir, -1, 0)
+			pos = pos.ShiftDir(dir)
+		}
+		addMovingBlock(head, headPos, dir, -1, 0)
+		PlaySoundAt("tile.piston.out", 0.5, 1, sndPos)
+	case 1: // Retract
+		if p.sticky {
+			pos := headPos.ShiftDir(dir)
+			b := chunkMap.Block(pos.X, pos.Y, pos.Z)
+			if !b.Is(Blocks.Air) && b.Renderable() {
+				addMovingBlock(b, headPos, dir, 1, 0)
+			}
+		}
+		addMovingBlock(head, headPos, dir, 0, -1)
+		PlaySoundAt("tile.piston.in", 0.5, 1, sndPos)
+	}
+}
+
+func esPistonAdd(p *pistonComponent, b BlockComponent) {
+	p.position = b.Position()
+}
+
+// movingBlockComponent draws a block as it's pushed or pulled
+// by a piston. The block moves along its direction from the
+// start offset to the end offset.
+type movingBlockComponent struct {
+	block      Block
+	position   Position
+	dir        direction.Type
+	start, end float64
+	progress   float64
+	model      *render.Model
+}
+
+// MovingBlockComponent is implemented by blocks being moved
+// by pistons.
+type MovingBlockComponent interface {
+	Done() bool
+}
+
+func (m *movingBlockComponent) Model() *render.Model { return m.model }
+func (m *movingBlockComponent) Done() bool           { return m.progress >= 1 }
+
+func addMovingBlock(b Block, pos Position, dir direction.Type, start, end float64) {
+	type movingBlock struct {
+		blockComponent
+		movingBlockComponent
+	}
+	mb := &movingBlock{}
+	mb.block = b
+	mb.dir = dir
+	mb.start, mb.end = start, end
+	mb.SetPosition(pos)
+	Client.entities.container.AddEntity(mb)
+	Client.movingBlocks = append(Client.movingBlocks, mb)
+}
+
+func (m *movingBlockComponent) updateMatrix() {
+	offset := m.start + (m.end-m.start)*m.progress
+	dx, dy, dz := m.dir.Offset()
+	m.model.Matrix[0] = mgl32.Translate3D(
+		float32(float64(m.position.X)+float64(dx)*offset),
+		-float32(float64(m.position.Y)+float64(dy)*offset),
+		float32(float64(m.position.Z)+float64(dz)*offset),
+	)
+}
+
+func esMovingBlockAdd(m *movingBlockComponent, p BlockComponent) {
+	m.position = p.Position()
+	m.model = render.NewModel([][]*render.ModelVertex{
+		blockModelVertices(m.block),
+	})
+	m.updateMatrix()
+}
+
+func esMovingBlockRemove(m *movingBlockComponent) {
+	if m.model != nil {
+		m.model.Free()
+		m.model = nil
+	}
+}
+
+func esMovingBlockTick(m *movingBlockComponent) {
+	// Vanilla moves blocks half a block a tick
+	m.progress += 0.5 * (Client.delta / 3)
+	if m.progress > 1 {
+		m.progress = 1
+	}
+	m.updateMatrix()
+}
+
+// blockModelVertices returns the vertices of the block's model
+// for use in entity models.
+func blockModelVertices(b Block) (out []*render.ModelVertex) {
+	variants := b.Models()
+	if variants == nil {
+		return nil
+	}
+	mdl := variants.selectModel(rand.New(rand.NewSource(0)))
+	if mdl == nil {
+		return nil
+	}
+	for _, f := range mdl.faces {
+		shade := 1.0
+		switch f.facing {
+		case direction.East, direction.West:
+			shade = 0.8
+		case direction.North, direction.South:
+			shade = 0.6
+		}
+		col := byte(255 * shade)
+		for i, vert := range f.vertices {
+			tex := f.verticesTexture[i]
+			rect := tex.Rect()
+			out = append(out, &render.ModelVertex{
+				X:        vert.X,
+				Y:        vert.Y,
+				Z:        vert.Z,
+				Texture:  tex,
+				TextureX: float64(vert.TOffsetX) / float64(16*rect.Width),
+				TextureY: float64(vert.TOffsetY) / float64(16*rect.Height),
+				R:        col,
+				G:        col,
+				B:        col,
+				A:        255,
+			})
+		}
+	}
+	return out
+}
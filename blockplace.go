@@ -0,0 +1,289 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/thinkofdeath/steven/type/direction"
+)
+
+// placePredictionTimeout is how long (in 1/60ths of a second) a
+// predicted block is kept without the server sending the block
+// back before it is removed again.
+const placePredictionTimeout = 3 * 60
+
+// blockPlacement describes how a block is being placed.
+type blockPlacement struct {
+	// Pos is where the block is being placed
+	Pos Position
+	// Face is the face of the clicked block the new block is
+	// being placed against
+	Face direction.Type
+	// Cursor is where on the clicked block the player clicked
+	Cursor mgl32.Vec3
+	// Facing is the horizontal direction the player is facing
+	Facing direction.Type
+	// X, Y, Z is the position of the player's eyes
+	X, Y, Z float64
+}
+
+// topHalf returns whether a block that can be in either half of
+// the space (e.g. slabs) should be placed in the top half.
+func (p blockPlacement) topHalf() bool {
+	return p.Face == direction.Down || (p.Face != direction.Up && p.Cursor.Y() > 0.5)
+}
+
+// lookingAt returns the direction a block that can face any way
+// (e.g. pistons) should face to point at the player.
+func (p blockPlacement) lookingAt() direction.Type {
+	if math.Abs(p.X-float64(p.Pos.X)) < 2 && math.Abs(p.Z-float64(p.Pos.Z)) < 2 {
+		if p.Y-float64(p.Pos.Y) > 2 {
+			return direction.Up
+		}
+		if float64(p.Pos.Y)-p.Y > 0 {
+			return direction.Down
+		}
+	}
+	return p.Facing.Opposite()
+}
+
+// placePredictions tracks the blocks placed locally that the
+// server hasn't sent back yet.
+type placePredictions struct {
+	blocks map[Position]*placePrediction
+}
+
+type placePrediction struct {
+	// old is the block that was replaced and is restored
+	// if the server never replies
+	old       Block
+	remaining float64
+}
+
+// add places the block locally.
+func (pp *placePredictions) add(pos Position, b Block) {
+	if pp.blocks == nil {
+		pp.blocks = map[Position]*placePrediction{}
+	}
+	p, ok := pp.blocks[pos]
+	if !ok {
+		// Keep the original block if the position was already
+		// predicted
+		p = &placePrediction{old: chunkMap.Block(pos.X, pos.Y, pos.Z)}
+		pp.blocks[pos] = p
+	}
+	p.remaining = placePredictionTimeout
+	chunkMap.SetBlock(b, pos.X, pos.Y, pos.Z)
+	chunkMap.UpdateBlock(pos.X, pos.Y, pos.Z)
+}
+
+// resolve stops tracking the position once the server has sent
+// the block for it. The server's block replaces the predicted one
+// so a wrong prediction is undone by the server.
+func (pp *placePredictions) resolve(pos Position) {
+	delete(pp.blocks, pos)
+}
+
+// resolveChunk stops tracking every position in the chunk, used
+// when the server resends the whole chunk.
+func (pp *placePredictions) resolveChunk(cp chunkPosition) {
+	for pos := range pp.blocks {
+		if pos.X>>4 == cp.X && pos.Z>>4 == cp.Z {
+			delete(pp.blocks, pos)
+		}
+	}
+}
+
+// clear forgets every prediction without rolling them back, used
+// when the world is replaced.
+func (pp *placePredictions) clear() {
+	pp.blocks = nil
+}
+
+// tick rolls back the predictions that the server hasn't replied
+// to in time.
+func (pp *placePredictions) tick(delta float64) {
+	for pos, p := range pp.blocks {
+		p.remaining -= delta
+		if p.remaining > 0 {
+			continue
+		}
+		delete(pp.blocks, pos)
+		chunkMap.SetBlock(p.old, pos.X, pos.Y, pos.Z)
+		chunkMap.UpdateBlock(pos.X, pos.Y, pos.Z)
+	}
+}
+
+// predictPlacement places the block that the server is expected to
+// place for a right click on the passed block so that building
+// doesn't have to wait for the server.
+func (c *ClientState) predictPlacement(pos Position, target Block, face direction.Type, cursor mgl32.Vec3) {
+	if c.GameMode == gmAdventure || c.GameMode == gmSpecator {
+		return
+	}
+	item := c.playerInventory.Items[c.currentHotbarSlot+invPlayerHotbarOffset]
+	if item == nil {
+		return
+	}
+	b := itemPlacedBlock(item)
+	if b == nil {
+		return
+	}
+	// Clicking on a block that can be used uses it instead of
+	// placing unless sneaking
+	if !c.isSneaking && blockUsable(target) {
+		return
+	}
+	// Slabs placed on to slabs of the same type become double
+	// slabs which isn't predicted
+	if _, ok := target.(*blockSlab); ok && target.Is(b.BlockSet()) {
+		return
+	}
+
+	if !blockReplaceable(target) {
+		pos = pos.ShiftDir(face)
+		if !blockReplaceable(chunkMap.Block(pos.X, pos.Y, pos.Z)) {
+			return
+		}
+	}
+	if pos.Y < 0 || pos.Y > 255 {
+		return
+	}
+
+	p := blockPlacement{
+		Pos:    pos,
+		Face:   face,
+		Cursor: cursor,
+		Facing: c.horizontalFacing(),
+		X:      c.X,
+		Y:      c.Y + playerHeight,
+		Z:      c.Z,
+	}
+	placed := b.placementState(p)
+	if placed == nil || c.blockCollidesWithPlayer(placed, pos) {
+		return
+	}
+
+	// Doors fill the space above them as well
+	if door, ok := placed.(*blockDoor); ok {
+		above := pos.ShiftDir(direction.Up)
+		upper := door.Set("half", doorUpper)
+		if above.Y > 255 || !blockReplaceable(chunkMap.Block(above.X, above.Y, above.Z)) ||
+			c.blockCollidesWithPlayer(upper, above) {
+			return
+		}
+		c.placePredictions.add(pos, placed)
+		c.placePredictions.add(above, upper)
+		return
+	}
+	c.placePredictions.add(pos, placed)
+}
+
+// horizontalFacing returns the horizontal direction the player is
+// facing.
+func (c *ClientState) horizontalFacing() direction.Type {
+	x := -math.Cos(c.Yaw - math.Pi/2)
+	z := math.Sin(c.Yaw - math.Pi/2)
+	if math.Abs(x) > math.Abs(z) {
+		if x > 0 {
+			return direction.East
+		}
+		return direction.West
+	}
+	if z > 0 {
+		return direction.South
+	}
+	return direction.North
+}
+
+// blockCollidesWithPlayer returns whether the block would be inside
+// the player if placed at the position.
+func (c *ClientState) blockCollidesWithPlayer(b Block, pos Position) bool {
+	if !b.Collidable() {
+		return false
+	}
+	player := c.physics.box()
+	for _, bb := range b.CollisionBounds() {
+		if boxFromAABB(bb).offset(float64(pos.X), float64(pos.Y), float64(pos.Z)).intersects(player) {
+			return true
+		}
+	}
+	return false
+}
+
+// itemPlacedBlock returns the block placed by the item or nil if
+// it doesn't place a block.
+func itemPlacedBlock(item *ItemStack) Block {
+	if bi, ok := item.Type.(*blockItem); ok {
+		return bi.block
+	}
+	// Items that place a block without being one
+	switch item.rawID {
+	case 324:
+		return Blocks.WoodenDoor.Base
+	case 330:
+		return Blocks.IronDoor.Base
+	case 356:
+		return Blocks.RepeaterUnpowered.Base
+	case 404:
+		return Blocks.ComparatorUnpowered.Base
+	case 427:
+		return Blocks.SpruceDoor.Base
+	case 428:
+		return Blocks.BirchDoor.Base
+	case 429:
+		return Blocks.JungleDoor.Base
+	case 430:
+		return Blocks.AcaciaDoor.Base
+	case 431:
+		return Blocks.DarkOakDoor.Base
+	}
+	return nil
+}
+
+// blockReplaceable returns whether placing a block against the
+// passed block replaces it instead of placing next to it.
+func blockReplaceable(b Block) bool {
+	switch b := b.(type) {
+	case *blockLiquid, *blockTallGrass, *blockDeadBush, *blockVines, *blockFire:
+		return true
+	case *blockSnowLayer:
+		return b.Layers == 1
+	}
+	return b.Is(Blocks.Air)
+}
+
+// blockUsable returns whether right clicking the block does
+// something other than placing a block against it.
+func blockUsable(b Block) bool {
+	switch b.(type) {
+	case *blockChest, *blockDispenser, *blockFenceGate, *blockLever,
+		*blockButton, *blockRepeater, *blockComparator, *blockNoteBlock,
+		*blockAnvil, *blockBrewingStand, *blockHopper, *blockBed,
+		*blockCauldron:
+		return true
+	case *blockDoor:
+		return !b.Is(Blocks.IronDoor)
+	case *blockTrapDoor:
+		return !b.Is(Blocks.IronTrapDoor)
+	}
+	bs := b.BlockSet()
+	return bs == Blocks.CraftingTable || bs == Blocks.Furnace || bs == Blocks.FurnaceLit ||
+		bs == Blocks.EnchantingTable || bs == Blocks.Beacon || bs == Blocks.Jukebox ||
+		bs == Blocks.Cake || bs == Blocks.DragonEgg || bs == Blocks.CommandBlock ||
+		bs == Blocks.DaylightDetector || bs == Blocks.DaylightDetectorInverted
+}
@@ -0,0 +1,414 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/thinkofdeath/steven/type/direction"
+	"github.com/thinkofdeath/steven/type/vmath"
+)
+
+// Repeater
+
+type blockRepeater struct {
+	baseBlock
+	Facing direction.Type `state:"facing,2-5"`
+	Delay  int            `state:"delay,1-4"`
+	Locked bool           `state:"locked"`
+}
+
+func (b *blockRepeater) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+// UpdateState locks the repeater when a powered repeater or
+// comparator points into either of its sides.
+func (b *blockRepeater) UpdateState(x, y, z int) Block {
+	locked := false
+	for _, d := range []direction.Type{b.Facing.Clockwise(), b.Facing.CounterClockwise()} {
+		ox, oy, oz := d.Offset()
+		switch bl := chunkMap.Block(x+ox, y+oy, z+oz).(type) {
+		case *blockRepeater:
+			if bl.Is(Blocks.RepeaterPowered) && bl.Facing == d {
+				locked = true
+			}
+		case *blockComparator:
+			if bl.Powered && bl.Facing == d {
+				locked = true
+			}
+		}
+	}
+	return b.Set("locked", locked)
+}
+
+func (b *blockRepeater) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{
+			vmath.NewAABB(0, 0, 0, 1.0, 2/16.0, 1.0),
+		}
+	}
+	return b.bounds
+}
+
+func (b *blockRepeater) ModelVariant() string {
+	return fmt.Sprintf("delay=%d,facing=%s,locked=%t", b.Delay, b.Facing, b.Locked)
+}
+
+func (b *blockRepeater) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.Facing.Opposite())
+}
+
+// Comparator
+
+type comparatorMode int
+
+const (
+	cmCompare comparatorMode = iota
+	cmSubtract
+)
+
+func (c comparatorMode) String() string {
+	switch c {
+	case cmCompare:
+		return "compare"
+	case cmSubtract:
+		return "subtract"
+	}
+	return fmt.Sprintf("comparatorMode(%d)", c)
+}
+
+type blockComparator struct {
+	baseBlock
+	Facing  direction.Type `state:"facing,2-5"`
+	Mode    comparatorMode `state:"mode,0-1"`
+	Powered bool           `state:"powered"`
+}
+
+func (b *blockComparator) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+}
+
+func (b *blockComparator) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{
+			vmath.NewAABB(0, 0, 0, 1.0, 2/16.0, 1.0),
+		}
+	}
+	return b.bounds
+}
+
+func (b *blockComparator) ModelVariant() string {
+	return fmt.Sprintf("facing=%s,mode=%s,powered=%t", b.Facing, b.Mode, b.Powered)
+}
+
+func (b *blockComparator) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.Facing.Opposite()).Set("mode", cmCompare).Set("powered", false)
+}
+
+// Button
+
+type blockButton struct {
+	baseBlock
+	Facing  direction.Type `state:"facing,0-5"`
+	Powered bool           `state:"powered"`
+}
+
+func (b *blockButton) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+	b.collidable = false
+}
+
+func (b *blockButton) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		depth := float32(2 / 16.0)
+		if b.Powered {
+			depth = 1 / 16.0
+		}
+		var bb vmath.AABB
+		switch b.Facing {
+		case direction.Up:
+			bb = vmath.NewAABB(5/16.0, 0, 6/16.0, 11/16.0, depth, 10/16.0)
+		case direction.Down:
+			bb = vmath.NewAABB(5/16.0, 1-depth, 6/16.0, 11/16.0, 1, 10/16.0)
+		case direction.East:
+			bb = vmath.NewAABB(0, 6/16.0, 5/16.0, depth, 10/16.0, 11/16.0)
+		case direction.West:
+			bb = vmath.NewAABB(1-depth, 6/16.0, 5/16.0, 1, 10/16.0, 11/16.0)
+		case direction.South:
+			bb = vmath.NewAABB(5/16.0, 6/16.0, 0, 11/16.0, 10/16.0, depth)
+		case direction.North:
+			bb = vmath.NewAABB(5/16.0, 6/16.0, 1-depth, 11/16.0, 10/16.0, 1)
+		}
+		b.bounds = []vmath.AABB{bb}
+	}
+	return b.bounds
+}
+
+func (b *blockButton) ModelVariant() string {
+	return fmt.Sprintf("facing=%s,powered=%t", b.Facing, b.Powered)
+}
+
+func (b *blockButton) placementState(p blockPlacement) Block {
+	return b.Set("facing", p.Face).Set("powered", false)
+}
+
+// Lever
+
+// leverFacing is the side the lever is attached to, the floor and
+// ceiling variants also include the axis the lever moves along.
+type leverFacing int
+
+const (
+	lfDownX leverFacing = iota
+	lfEast
+	lfWest
+	lfSouth
+	lfNorth
+	lfUpZ
+	lfUpX
+	lfDownZ
+)
+
+func (l leverFacing) String() string {
+	switch l {
+	case lfDownX:
+		return "down_x"
+	case lfEast:
+		return "east"
+	case lfWest:
+		return "west"
+	case lfSouth:
+		return "south"
+	case lfNorth:
+		return "north"
+	case lfUpZ:
+		return "up_z"
+	case lfUpX:
+		return "up_x"
+	case lfDownZ:
+		return "down_z"
+	}
+	return fmt.Sprintf("leverFacing(%d)", l)
+}
+
+type blockLever struct {
+	baseBlock
+	Facing  leverFacing `state:"facing,0-7"`
+	Powered bool        `state:"powered"`
+}
+
+func (b *blockLever) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+	b.collidable = false
+}
+
+func (b *blockLever) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		var bb vmath.AABB
+		switch b.Facing {
+		case lfEast:
+			bb = vmath.NewAABB(0, 0.2, 5/16.0, 6/16.0, 0.8, 11/16.0)
+		case lfWest:
+			bb = vmath.NewAABB(10/16.0, 0.2, 5/16.0, 1, 0.8, 11/16.0)
+		case lfSouth:
+			bb = vmath.NewAABB(5/16.0, 0.2, 0, 11/16.0, 0.8, 6/16.0)
+		case lfNorth:
+			bb = vmath.NewAABB(5/16.0, 0.2, 10/16.0, 11/16.0, 0.8, 1)
+		case lfUpZ, lfUpX:
+			bb = vmath.NewAABB(0.25, 0, 0.25, 0.75, 0.6, 0.75)
+		case lfDownX, lfDownZ:
+			bb = vmath.NewAABB(0.25, 0.4, 0.25, 0.75, 1, 0.75)
+		}
+		b.bounds = []vmath.AABB{bb}
+	}
+	return b.bounds
+}
+
+func (b *blockLever) ModelVariant() string {
+	return fmt.Sprintf("facing=%s,powered=%t", b.Facing, b.Powered)
+}
+
+func (b *blockLever) placementState(p blockPlacement) Block {
+	var facing leverFacing
+	alongX := p.Facing == direction.East || p.Facing == direction.West
+	switch p.Face {
+	case direction.Up:
+		facing = lfUpZ
+		if alongX {
+			facing = lfUpX
+		}
+	case direction.Down:
+		facing = lfDownZ
+		if alongX {
+			facing = lfDownX
+		}
+	case direction.East:
+		facing = lfEast
+	case direction.West:
+		facing = lfWest
+	case direction.South:
+		facing = lfSouth
+	case direction.North:
+		facing = lfNorth
+	}
+	return b.Set("facing", facing).Set("powered", false)
+}
+
+// Pressure plate
+
+type blockPressurePlate struct {
+	baseBlock
+	Powered bool `state:"powered"`
+}
+
+func (b *blockPressurePlate) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+	b.collidable = false
+}
+
+func (b *blockPressurePlate) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{pressurePlateBounds(b.Powered)}
+	}
+	return b.bounds
+}
+
+func (b *blockPressurePlate) ModelVariant() string {
+	return fmt.Sprintf("powered=%t", b.Powered)
+}
+
+// Weighted pressure plate
+
+type blockWeightedPressurePlate struct {
+	baseBlock
+	Power int `state:"power,0-15"`
+}
+
+func (b *blockWeightedPressurePlate) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+	b.collidable = false
+}
+
+func (b *blockWeightedPressurePlate) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		b.bounds = []vmath.AABB{pressurePlateBounds(b.Power > 0)}
+	}
+	return b.bounds
+}
+
+func (b *blockWeightedPressurePlate) ModelVariant() string {
+	return fmt.Sprintf("power=%d", b.Power)
+}
+
+func pressurePlateBounds(pressed bool) vmath.AABB {
+	height := float32(1 / 16.0)
+	if pressed {
+		height = 0.5 / 16.0
+	}
+	return vmath.NewAABB(1/16.0, 0, 1/16.0, 15/16.0, height, 15/16.0)
+}
+
+// Tripwire hook
+
+type blockTripwireHook struct {
+	baseBlock
+	Facing   direction.Type `state:"facing,2-5"`
+	Attached bool           `state:"attached"`
+	Powered  bool           `state:"powered"`
+}
+
+func (b *blockTripwireHook) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+	b.collidable = false
+}
+
+func (b *blockTripwireHook) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		var bb vmath.AABB
+		switch b.Facing {
+		case direction.North:
+			bb = vmath.NewAABB(5/16.0, 0, 10/16.0, 11/16.0, 10/16.0, 1)
+		case direction.South:
+			bb = vmath.NewAABB(5/16.0, 0, 0, 11/16.0, 10/16.0, 6/16.0)
+		case direction.West:
+			bb = vmath.NewAABB(10/16.0, 0, 5/16.0, 1, 10/16.0, 11/16.0)
+		case direction.East:
+			bb = vmath.NewAABB(0, 0, 5/16.0, 6/16.0, 10/16.0, 11/16.0)
+		}
+		b.bounds = []vmath.AABB{bb}
+	}
+	return b.bounds
+}
+
+func (b *blockTripwireHook) ModelVariant() string {
+	return fmt.Sprintf("attached=%t,facing=%s,powered=%t", b.Attached, b.Facing, b.Powered)
+}
+
+// Tripwire
+
+type blockTripwire struct {
+	baseBlock
+	Powered  bool `state:"powered"`
+	Attached bool `state:"attached"`
+	Disarmed bool `state:"disarmed"`
+	North    bool `state:"north"`
+	South    bool `state:"south"`
+	East     bool `state:"east"`
+	West     bool `state:"west"`
+}
+
+func (b *blockTripwire) load(tag reflect.StructTag) {
+	b.cullAgainst = false
+	b.collidable = false
+}
+
+// UpdateState connects the tripwire to neighbouring tripwire and
+// to hooks facing towards it.
+func (b *blockTripwire) UpdateState(x, y, z int) Block {
+	var block Block = b
+	for _, d := range direction.Values {
+		if d < 2 {
+			continue
+		}
+		ox, oy, oz := d.Offset()
+		connected := false
+		switch bl := chunkMap.Block(x+ox, y+oy, z+oz).(type) {
+		case *blockTripwire:
+			connected = true
+		case *blockTripwireHook:
+			connected = bl.Facing == d.Opposite()
+		}
+		block = block.Set(d.String(), connected)
+	}
+	return block
+}
+
+func (b *blockTripwire) CollisionBounds() []vmath.AABB {
+	if b.bounds == nil {
+		bb := vmath.NewAABB(0, 0, 0, 1.0, 0.5, 1.0)
+		if b.Attached {
+			bb = vmath.NewAABB(0, 1/16.0, 0, 1.0, 2.5/16.0, 1.0)
+		}
+		b.bounds = []vmath.AABB{bb}
+	}
+	return b.bounds
+}
+
+func (b *blockTripwire) ModelVariant() string {
+	return fmt.Sprintf("attached=%t,east=%t,north=%t,south=%t,west=%t",
+		b.Attached, b.East, b.North, b.South, b.West)
+}
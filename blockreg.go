@@ -53,6 +53,7 @@ func init() {
 	registerBlockType("skull", &blockSkull{})
 	registerBlockType("crop", &blockCrop{})
 	registerBlockType("farmland", &blockFarmland{})
+	registerBlockType("soulSand", &blockSoulSand{})
 	registerBlockType("portal", &blockPortal{})
 	registerBlockType("lilypad", &blockLilypad{})
 	registerBlockType("stonebrick", &blockStoneBrick{})
@@ -64,4 +65,24 @@ func init() {
 	registerBlockType("quartzBlock", &blockQuartzBlock{})
 	registerBlockType("snowLayer", &blockSnowLayer{})
 	registerBlockType("doublePlant", &blockDoublePlant{})
+	registerBlockType("chest", &blockChest{})
+	registerBlockType("noteBlock", &blockNoteBlock{})
+	registerBlockType("repeater", &blockRepeater{})
+	registerBlockType("comparator", &blockComparator{})
+	registerBlockType("button", &blockButton{})
+	registerBlockType("lever", &blockLever{})
+	registerBlockType("pressurePlate", &blockPressurePlate{})
+	registerBlockType("weightedPressurePlate", &blockWeightedPressurePlate{})
+	registerBlockType("tripwireHook", &blockTripwireHook{})
+	registerBlockType("tripwire", &blockTripwire{})
+	registerBlockType("trapDoor", &blockTrapDoor{})
+	registerBlockType("ladder", &blockLadder{})
+	registerBlockType("anvil", &blockAnvil{})
+	registerBlockType("cauldron", &blockCauldron{})
+	registerBlockType("brewingStand", &blockBrewingStand{})
+	registerBlockType("hopper", &blockHopper{})
+	registerBlockType("enchantingTable", &blockEnchantingTable{})
+	registerBlockType("endPortalFrame", &blockEndPortalFrame{})
+	registerBlockType("cocoa", &blockCocoa{})
+	registerBlockType("netherWart", &blockNetherWart{})
 }
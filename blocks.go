@@ -20,222 +20,7 @@ import (
 	"unicode"
 )
 
-// Valid blocks.
-var Blocks = struct {
-	Air                        *BlockSet `cullAgainst:"false" collidable:"false" renderable:"false"`
-	Stone                      *BlockSet `type:"stone"`
-	Grass                      *BlockSet `type:"grass"`
-	Dirt                       *BlockSet
-	Cobblestone                *BlockSet
-	Planks                     *BlockSet `type:"planks"`
-	Sapling                    *BlockSet `type:"sapling"`
-	Bedrock                    *BlockSet `hardness:"Inf"`
-	FlowingWater               *BlockSet `type:"liquid"`
-	Water                      *BlockSet `type:"liquid"`
-	FlowingLava                *BlockSet `type:"liquid" lava:"true"`
-	Lava                       *BlockSet `type:"liquid" lava:"true"`
-	Sand                       *BlockSet `hardness:"0.5"`
-	Gravel                     *BlockSet
-	GoldOre                    *BlockSet
-	IronOre                    *BlockSet
-	CoalOre                    *BlockSet
-	Log                        *BlockSet `type:"log"`
-	Leaves                     *BlockSet `type:"leaves"`
-	Sponge                     *BlockSet `type:"sponge"`
-	Glass                      *BlockSet `cullAgainst:"false"`
-	LapisOre                   *BlockSet
-	LapisBlock                 *BlockSet
-	Dispenser                  *BlockSet `type:"dispenser"`
-	Sandstone                  *BlockSet
-	NoteBlock                  *BlockSet `mc:"noteblock"`
-	Bed                        *BlockSet `type:"bed"`
-	GoldenRail                 *BlockSet `type:"poweredRail"`
-	DetectorRail               *BlockSet `type:"poweredRail"`
-	StickyPiston               *BlockSet `type:"piston"`
-	Web                        *BlockSet `cullAgainst:"false" collidable:"false"`
-	TallGrass                  *BlockSet `type:"tallGrass" mc:"tallgrass"`
-	DeadBush                   *BlockSet `type:"deadBush" mc:"deadbush"`
-	Piston                     *BlockSet `type:"piston"`
-	PistonHead                 *BlockSet `type:"pistonHead"`
-	Wool                       *BlockSet `type:"wool"`
-	PistonExtension            *BlockSet `renderable:"false"`
-	YellowFlower               *BlockSet `type:"yellowFlower"`
-	RedFlower                  *BlockSet `type:"redFlower"`
-	BrownMushroom              *BlockSet `cullAgainst:"false" collidable:"false"`
-	RedMushroom                *BlockSet `cullAgainst:"false" collidable:"false"`
-	GoldBlock                  *BlockSet
-	IronBlock                  *BlockSet
-	DoubleStoneSlab            *BlockSet `type:"slabDoubleSeamless" variant:"stone"`
-	StoneSlab                  *BlockSet `type:"slab" variant:"stone"`
-	BrickBlock                 *BlockSet
-	TNT                        *BlockSet `mc:"tnt"`
-	BookShelf                  *BlockSet `mc:"bookshelf"`
-	MossyCobblestone           *BlockSet
-	Obsidian                   *BlockSet
-	Torch                      *BlockSet `type:"torch" model:"torch"`
-	Fire                       *BlockSet `type:"fire"`
-	MobSpawner                 *BlockSet
-	OakStairs                  *BlockSet `type:"stairs"`
-	Chest                      *BlockSet
-	RedstoneWire               *BlockSet `type:"redstone"`
-	DiamondOre                 *BlockSet
-	DiamondBlock               *BlockSet
-	CraftingTable              *BlockSet
-	Wheat                      *BlockSet `type:"crop"`
-	Farmland                   *BlockSet `type:"farmland"`
-	Furnace                    *BlockSet
-	FurnaceLit                 *BlockSet
-	StandingSign               *BlockSet `type:"floorSign"`
-	WoodenDoor                 *BlockSet `type:"door"`
-	Ladder                     *BlockSet
-	Rail                       *BlockSet `type:"rail"`
-	StoneStairs                *BlockSet `type:"stairs"`
-	WallSign                   *BlockSet `type:"wallSign"`
-	Lever                      *BlockSet
-	StonePressurePlate         *BlockSet
-	IronDoor                   *BlockSet `type:"door"`
-	WoodenPressurePlate        *BlockSet
-	RedstoneOre                *BlockSet
-	RedstoneOreLit             *BlockSet
-	RedstoneTorchUnlit         *BlockSet `type:"torch" model:"unlit_redstone_torch"`
-	RedstoneTorch              *BlockSet `type:"torch" model:"redstone_torch"`
-	StoneButton                *BlockSet
-	SnowLayer                  *BlockSet `type:"snowLayer"`
-	Ice                        *BlockSet `translucent:"true" cullAgainst:"false"`
-	Snow                       *BlockSet
-	Cactus                     *BlockSet `type:"cactus"`
-	Clay                       *BlockSet
-	Reeds                      *BlockSet `cullAgainst:"false" collidable:"false"`
-	Jukebox                    *BlockSet
-	Fence                      *BlockSet `type:"fence"`
-	Pumpkin                    *BlockSet
-	Netherrack                 *BlockSet
-	SoulSand                   *BlockSet
-	Glowstone                  *BlockSet
-	Portal                     *BlockSet `type:"portal"`
-	PumpkinLit                 *BlockSet
-	Cake                       *BlockSet
-	RepeaterUnpowered          *BlockSet
-	RepeaterPowered            *BlockSet
-	StainedGlass               *BlockSet `type:"stainedGlass"`
-	TrapDoor                   *BlockSet
-	MonsterEgg                 *BlockSet
-	StoneBrick                 *BlockSet `mc:"stonebrick" type:"stonebrick"`
-	BrownMushroomBlock         *BlockSet
-	RedMushroomBlock           *BlockSet
-	IronBars                   *BlockSet `type:"connectable"`
-	GlassPane                  *BlockSet `type:"connectable"`
-	MelonBlock                 *BlockSet
-	PumpkinStem                *BlockSet
-	MelonStem                  *BlockSet
-	Vine                       *BlockSet `type:"vines"`
-	FenceGate                  *BlockSet `type:"fenceGate"`
-	BrickStairs                *BlockSet `type:"stairs"`
-	StoneBrickStairs           *BlockSet `type:"stairs"`
-	Mycelium                   *BlockSet
-	Waterlily                  *BlockSet `type:"lilypad"`
-	NetherBrick                *BlockSet
-	NetherBrickFence           *BlockSet `type:"fence" wood:"false"`
-	NetherBrickStairs          *BlockSet `type:"stairs"`
-	NetherWart                 *BlockSet
-	EnchantingTable            *BlockSet
-	BrewingStand               *BlockSet
-	Cauldron                   *BlockSet
-	EndPortal                  *BlockSet `collidable:"false"`
-	EndPortalFrame             *BlockSet
-	EndStone                   *BlockSet
-	DragonEgg                  *BlockSet
-	RedstoneLamp               *BlockSet
-	RedstoneLampLit            *BlockSet
-	DoubleWoodenSlab           *BlockSet `type:"slabDouble" variant:"wood"`
-	WoodenSlab                 *BlockSet `type:"slab" variant:"wood"`
-	Cocoa                      *BlockSet
-	SandstoneStairs            *BlockSet `type:"stairs"`
-	EmeraldOre                 *BlockSet
-	EnderChest                 *BlockSet
-	TripwireHook               *BlockSet
-	Tripwire                   *BlockSet
-	EmeraldBlock               *BlockSet
-	SpruceStairs               *BlockSet `type:"stairs"`
-	BirchStairs                *BlockSet `type:"stairs"`
-	JungleStairs               *BlockSet `type:"stairs"`
-	CommandBlock               *BlockSet
-	Beacon                     *BlockSet `cullAgainst:"false"`
-	CobblestoneWall            *BlockSet `type:"wall"`
-	FlowerPot                  *BlockSet
-	Carrots                    *BlockSet `type:"crop"`
-	Potatoes                   *BlockSet `type:"crop"`
-	WoodenButton               *BlockSet
-	Skull                      *BlockSet `type:"skull"`
-	Anvil                      *BlockSet
-	TrappedChest               *BlockSet
-	LightWeightedPressurePlate *BlockSet
-	HeavyWeightedPressurePlate *BlockSet
-	ComparatorUnpowered        *BlockSet
-	ComparatorPowered          *BlockSet
-	DaylightDetector           *BlockSet
-	RedstoneBlock              *BlockSet
-	QuartzOre                  *BlockSet
-	Hopper                     *BlockSet
-	QuartzBlock                *BlockSet `type:"quartzBlock"`
-	QuartzStairs               *BlockSet `type:"stairs"`
-	ActivatorRail              *BlockSet `type:"poweredRail"`
-	Dropper                    *BlockSet `type:"dispenser"`
-	StainedHardenedClay        *BlockSet `type:"stainedClay"`
-	StainedGlassPane           *BlockSet `type:"stainedGlassPane"`
-	Leaves2                    *BlockSet `type:"leaves" second:"true"`
-	Log2                       *BlockSet `type:"log" second:"true"`
-	AcaciaStairs               *BlockSet `type:"stairs"`
-	DarkOakStairs              *BlockSet `type:"stairs"`
-	Slime                      *BlockSet
-	Barrier                    *BlockSet `cullAgainst:"false" renderable:"false"`
-	IronTrapDoor               *BlockSet
-	Prismarine                 *BlockSet
-	SeaLantern                 *BlockSet
-	HayBlock                   *BlockSet
-	Carpet                     *BlockSet `type:"carpet"`
-	HardenedClay               *BlockSet
-	CoalBlock                  *BlockSet
-	PackedIce                  *BlockSet
-	DoublePlant                *BlockSet `type:"doublePlant"`
-	StandingBanner             *BlockSet
-	WallBanner                 *BlockSet
-	DaylightDetectorInverted   *BlockSet
-	RedSandstone               *BlockSet
-	RedSandstoneStairs         *BlockSet `type:"stairs"`
-	DoubleStoneSlab2           *BlockSet `type:"slabDoubleSeamless" variant:"stone2"`
-	StoneSlab2                 *BlockSet `type:"slab" variant:"stone2"`
-	SpruceFenceGate            *BlockSet `type:"fenceGate"`
-	BirchFenceGate             *BlockSet `type:"fenceGate"`
-	JungleFenceGate            *BlockSet `type:"fenceGate"`
-	DarkOakFenceGate           *BlockSet `type:"fenceGate"`
-	AcaciaFenceGate            *BlockSet `type:"fenceGate"`
-	SpruceFence                *BlockSet `type:"fence"`
-	BirchFence                 *BlockSet `type:"fence"`
-	JungleFence                *BlockSet `type:"fence"`
-	DarkOakFence               *BlockSet `type:"fence"`
-	AcaciaFence                *BlockSet `type:"fence"`
-	SpruceDoor                 *BlockSet `type:"door"`
-	BirchDoor                  *BlockSet `type:"door"`
-	JungleDoor                 *BlockSet `type:"door"`
-	AcaciaDoor                 *BlockSet `type:"door"`
-	DarkOakDoor                *BlockSet `type:"door"`
-	EndRod                     *BlockSet
-	ChorusPlant                *BlockSet
-	ChorusFlower               *BlockSet
-	PurpurBlock                *BlockSet
-	PurpurPillar               *BlockSet
-	PurpurStairs               *BlockSet `type:"stairs"`
-	PurpurDoubleSlab           *BlockSet `type:"slabDoubleSeamless" variant:"purpur"`
-	PurpurSlab                 *BlockSet `type:"slab" variant:"purpur"`
-	EndBricks                  *BlockSet
-	Beetroots                  *BlockSet
-	GrassPath                  *BlockSet `cullAgainst:"false"`
-	EndGateway                 *BlockSet
-	StructureBlock             *BlockSet
-
-	MissingBlock *BlockSet `mc:"steven:missing_block"`
-}{}
+//go:generate block_builder blocks.json
 
 var blockTypes = map[string]reflect.Type{}
 
@@ -270,11 +55,16 @@ func init() {
 		}
 		nv := reflect.New(rT)
 		block := nv.Interface().(Block)
-		block.init(name)
+		block.init(name, tag)
 		if l, ok := block.(loadable); ok {
 			l.load(tag)
 		}
 		set := alloc(block)
+		set.dataRules = blockDataRules[ty]
+		set.clientStates = map[string]bool{}
+		for _, s := range blockClientStates[ty] {
+			set.clientStates[s] = true
+		}
 		fv.Set(reflect.ValueOf(set))
 	}
 
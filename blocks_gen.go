@@ -0,0 +1,560 @@
+// Generated by block_builder
+// Do not edit
+
+package steven
+
+// Valid blocks.
+var Blocks = struct {
+	Air                        *BlockSet `collidable:"false" cullAgainst:"false" renderable:"false"`
+	Stone                      *BlockSet `type:"stone"`
+	Grass                      *BlockSet `type:"grass"`
+	Dirt                       *BlockSet
+	Cobblestone                *BlockSet
+	Planks                     *BlockSet `type:"planks"`
+	Sapling                    *BlockSet `type:"sapling"`
+	Bedrock                    *BlockSet `hardness:"Inf"`
+	FlowingWater               *BlockSet `type:"liquid"`
+	Water                      *BlockSet `type:"liquid"`
+	FlowingLava                *BlockSet `type:"liquid" lava:"true"`
+	Lava                       *BlockSet `type:"liquid" lava:"true"`
+	Sand                       *BlockSet `hardness:"0.5"`
+	Gravel                     *BlockSet
+	GoldOre                    *BlockSet
+	IronOre                    *BlockSet
+	CoalOre                    *BlockSet
+	Log                        *BlockSet `type:"log"`
+	Leaves                     *BlockSet `type:"leaves"`
+	Sponge                     *BlockSet `type:"sponge"`
+	Glass                      *BlockSet `cullAgainst:"false"`
+	LapisOre                   *BlockSet
+	LapisBlock                 *BlockSet
+	Dispenser                  *BlockSet `type:"dispenser"`
+	Sandstone                  *BlockSet
+	NoteBlock                  *BlockSet `type:"noteBlock" mc:"noteblock"`
+	Bed                        *BlockSet `type:"bed"`
+	GoldenRail                 *BlockSet `type:"poweredRail"`
+	DetectorRail               *BlockSet `type:"poweredRail"`
+	StickyPiston               *BlockSet `type:"piston"`
+	Web                        *BlockSet `collidable:"false" cullAgainst:"false"`
+	TallGrass                  *BlockSet `type:"tallGrass" mc:"tallgrass"`
+	DeadBush                   *BlockSet `type:"deadBush" mc:"deadbush"`
+	Piston                     *BlockSet `type:"piston"`
+	PistonHead                 *BlockSet `type:"pistonHead"`
+	Wool                       *BlockSet `type:"wool"`
+	PistonExtension            *BlockSet `renderable:"false"`
+	YellowFlower               *BlockSet `type:"yellowFlower"`
+	RedFlower                  *BlockSet `type:"redFlower"`
+	BrownMushroom              *BlockSet `collidable:"false" cullAgainst:"false"`
+	RedMushroom                *BlockSet `collidable:"false" cullAgainst:"false"`
+	GoldBlock                  *BlockSet
+	IronBlock                  *BlockSet
+	DoubleStoneSlab            *BlockSet `type:"slabDoubleSeamless" variant:"stone"`
+	StoneSlab                  *BlockSet `type:"slab" variant:"stone"`
+	BrickBlock                 *BlockSet
+	TNT                        *BlockSet `mc:"tnt"`
+	BookShelf                  *BlockSet `mc:"bookshelf"`
+	MossyCobblestone           *BlockSet
+	Obsidian                   *BlockSet
+	Torch                      *BlockSet `type:"torch" light:"13" model:"torch"`
+	Fire                       *BlockSet `type:"fire"`
+	MobSpawner                 *BlockSet
+	OakStairs                  *BlockSet `type:"stairs"`
+	Chest                      *BlockSet `type:"chest"`
+	RedstoneWire               *BlockSet `type:"redstone"`
+	DiamondOre                 *BlockSet
+	DiamondBlock               *BlockSet
+	CraftingTable              *BlockSet
+	Wheat                      *BlockSet `type:"crop"`
+	Farmland                   *BlockSet `type:"farmland"`
+	Furnace                    *BlockSet
+	FurnaceLit                 *BlockSet
+	StandingSign               *BlockSet `type:"floorSign"`
+	WoodenDoor                 *BlockSet `type:"door"`
+	Ladder                     *BlockSet `type:"ladder" hardness:"0.4"`
+	Rail                       *BlockSet `type:"rail"`
+	StoneStairs                *BlockSet `type:"stairs"`
+	WallSign                   *BlockSet `type:"wallSign"`
+	Lever                      *BlockSet `type:"lever" hardness:"0.5"`
+	StonePressurePlate         *BlockSet `type:"pressurePlate" hardness:"0.5"`
+	IronDoor                   *BlockSet `type:"door"`
+	WoodenPressurePlate        *BlockSet `type:"pressurePlate" hardness:"0.5"`
+	RedstoneOre                *BlockSet
+	RedstoneOreLit             *BlockSet
+	RedstoneTorchUnlit         *BlockSet `type:"torch" mc:"unlit_redstone_torch" hardness:"0" model:"unlit_redstone_torch"`
+	RedstoneTorch              *BlockSet `type:"torch" hardness:"0" light:"7" model:"redstone_torch"`
+	StoneButton                *BlockSet `type:"button" hardness:"0.5"`
+	SnowLayer                  *BlockSet `type:"snowLayer"`
+	Ice                        *BlockSet `cullAgainst:"false" translucent:"true"`
+	Snow                       *BlockSet
+	Cactus                     *BlockSet `type:"cactus"`
+	Clay                       *BlockSet
+	Reeds                      *BlockSet `collidable:"false" cullAgainst:"false"`
+	Jukebox                    *BlockSet
+	Fence                      *BlockSet `type:"fence"`
+	Pumpkin                    *BlockSet
+	Netherrack                 *BlockSet
+	SoulSand                   *BlockSet `type:"soulSand"`
+	Glowstone                  *BlockSet
+	Portal                     *BlockSet `type:"portal"`
+	PumpkinLit                 *BlockSet
+	Cake                       *BlockSet
+	RepeaterUnpowered          *BlockSet `type:"repeater" mc:"unpowered_repeater" hardness:"0"`
+	RepeaterPowered            *BlockSet `type:"repeater" mc:"powered_repeater" hardness:"0"`
+	StainedGlass               *BlockSet `type:"stainedGlass"`
+	TrapDoor                   *BlockSet `type:"trapDoor" mc:"trapdoor" hardness:"3"`
+	MonsterEgg                 *BlockSet
+	StoneBrick                 *BlockSet `type:"stonebrick" mc:"stonebrick"`
+	BrownMushroomBlock         *BlockSet
+	RedMushroomBlock           *BlockSet
+	IronBars                   *BlockSet `type:"connectable"`
+	GlassPane                  *BlockSet `type:"connectable"`
+	MelonBlock                 *BlockSet
+	PumpkinStem                *BlockSet
+	MelonStem                  *BlockSet
+	Vine                       *BlockSet `type:"vines"`
+	FenceGate                  *BlockSet `type:"fenceGate"`
+	BrickStairs                *BlockSet `type:"stairs"`
+	StoneBrickStairs           *BlockSet `type:"stairs"`
+	Mycelium                   *BlockSet
+	Waterlily                  *BlockSet `type:"lilypad"`
+	NetherBrick                *BlockSet
+	NetherBrickFence           *BlockSet `type:"fence" wood:"false"`
+	NetherBrickStairs          *BlockSet `type:"stairs"`
+	NetherWart                 *BlockSet `type:"netherWart" hardness:"0"`
+	EnchantingTable            *BlockSet `type:"enchantingTable" hardness:"5"`
+	BrewingStand               *BlockSet `type:"brewingStand" hardness:"0.5" light:"1"`
+	Cauldron                   *BlockSet `type:"cauldron" hardness:"2"`
+	EndPortal                  *BlockSet `collidable:"false"`
+	EndPortalFrame             *BlockSet `type:"endPortalFrame" hardness:"Inf" light:"1"`
+	EndStone                   *BlockSet
+	DragonEgg                  *BlockSet
+	RedstoneLamp               *BlockSet
+	RedstoneLampLit            *BlockSet
+	DoubleWoodenSlab           *BlockSet `type:"slabDouble" variant:"wood"`
+	WoodenSlab                 *BlockSet `type:"slab" variant:"wood"`
+	Cocoa                      *BlockSet `type:"cocoa" hardness:"0.2"`
+	SandstoneStairs            *BlockSet `type:"stairs"`
+	EmeraldOre                 *BlockSet
+	EnderChest                 *BlockSet `type:"chest"`
+	TripwireHook               *BlockSet `type:"tripwireHook" hardness:"0"`
+	Tripwire                   *BlockSet `type:"tripwire" hardness:"0"`
+	EmeraldBlock               *BlockSet
+	SpruceStairs               *BlockSet `type:"stairs"`
+	BirchStairs                *BlockSet `type:"stairs"`
+	JungleStairs               *BlockSet `type:"stairs"`
+	CommandBlock               *BlockSet
+	Beacon                     *BlockSet `cullAgainst:"false" hardness:"3" light:"15"`
+	CobblestoneWall            *BlockSet `type:"wall"`
+	FlowerPot                  *BlockSet
+	Carrots                    *BlockSet `type:"crop"`
+	Potatoes                   *BlockSet `type:"crop"`
+	WoodenButton               *BlockSet `type:"button" hardness:"0.5"`
+	Skull                      *BlockSet `type:"skull"`
+	Anvil                      *BlockSet `type:"anvil" hardness:"5"`
+	TrappedChest               *BlockSet `type:"chest"`
+	LightWeightedPressurePlate *BlockSet `type:"weightedPressurePlate" hardness:"0.5"`
+	HeavyWeightedPressurePlate *BlockSet `type:"weightedPressurePlate" hardness:"0.5"`
+	ComparatorUnpowered        *BlockSet `type:"comparator" mc:"unpowered_comparator" hardness:"0"`
+	ComparatorPowered          *BlockSet `type:"comparator" mc:"powered_comparator" hardness:"0" light:"9"`
+	DaylightDetector           *BlockSet
+	RedstoneBlock              *BlockSet
+	QuartzOre                  *BlockSet
+	Hopper                     *BlockSet `type:"hopper" hardness:"3"`
+	QuartzBlock                *BlockSet `type:"quartzBlock"`
+	QuartzStairs               *BlockSet `type:"stairs"`
+	ActivatorRail              *BlockSet `type:"poweredRail"`
+	Dropper                    *BlockSet `type:"dispenser"`
+	StainedHardenedClay        *BlockSet `type:"stainedClay"`
+	StainedGlassPane           *BlockSet `type:"stainedGlassPane"`
+	Leaves2                    *BlockSet `type:"leaves" second:"true"`
+	Log2                       *BlockSet `type:"log" second:"true"`
+	AcaciaStairs               *BlockSet `type:"stairs"`
+	DarkOakStairs              *BlockSet `type:"stairs"`
+	Slime                      *BlockSet
+	Barrier                    *BlockSet `cullAgainst:"false" renderable:"false"`
+	IronTrapDoor               *BlockSet `type:"trapDoor" mc:"iron_trapdoor" hardness:"5"`
+	Prismarine                 *BlockSet
+	SeaLantern                 *BlockSet
+	HayBlock                   *BlockSet
+	Carpet                     *BlockSet `type:"carpet"`
+	HardenedClay               *BlockSet
+	CoalBlock                  *BlockSet
+	PackedIce                  *BlockSet
+	DoublePlant                *BlockSet `type:"doublePlant"`
+	StandingBanner             *BlockSet
+	WallBanner                 *BlockSet
+	DaylightDetectorInverted   *BlockSet
+	RedSandstone               *BlockSet
+	RedSandstoneStairs         *BlockSet `type:"stairs"`
+	DoubleStoneSlab2           *BlockSet `type:"slabDoubleSeamless" variant:"stone2"`
+	StoneSlab2                 *BlockSet `type:"slab" variant:"stone2"`
+	SpruceFenceGate            *BlockSet `type:"fenceGate"`
+	BirchFenceGate             *BlockSet `type:"fenceGate"`
+	JungleFenceGate            *BlockSet `type:"fenceGate"`
+	DarkOakFenceGate           *BlockSet `type:"fenceGate"`
+	AcaciaFenceGate            *BlockSet `type:"fenceGate"`
+	SpruceFence                *BlockSet `type:"fence"`
+	BirchFence                 *BlockSet `type:"fence"`
+	JungleFence                *BlockSet `type:"fence"`
+	DarkOakFence               *BlockSet `type:"fence"`
+	AcaciaFence                *BlockSet `type:"fence"`
+	SpruceDoor                 *BlockSet `type:"door"`
+	BirchDoor                  *BlockSet `type:"door"`
+	JungleDoor                 *BlockSet `type:"door"`
+	AcaciaDoor                 *BlockSet `type:"door"`
+	DarkOakDoor                *BlockSet `type:"door"`
+	EndRod                     *BlockSet
+	ChorusPlant                *BlockSet
+	ChorusFlower               *BlockSet
+	PurpurBlock                *BlockSet
+	PurpurPillar               *BlockSet
+	PurpurStairs               *BlockSet `type:"stairs"`
+	PurpurDoubleSlab           *BlockSet `type:"slabDoubleSeamless" variant:"purpur"`
+	PurpurSlab                 *BlockSet `type:"slab" variant:"purpur"`
+	EndBricks                  *BlockSet
+	Beetroots                  *BlockSet
+	GrassPath                  *BlockSet `cullAgainst:"false"`
+	EndGateway                 *BlockSet
+	StructureBlock             *BlockSet
+	MissingBlock               *BlockSet `mc:"steven:missing_block"`
+}{}
+
+// blockDataRules maps each block type to the rules used to
+// find the legacy data value of its states.
+var blockDataRules = map[string][]blockDataRule{
+	"anvil": {
+		{Values: map[string]map[string]int{
+			"damage": {"1": 4, "2": 8},
+			"facing": {"east": 3, "north": 2, "west": 1},
+		}},
+	},
+	"bed": {
+		{Values: map[string]map[string]int{
+			"facing":   {"east": 3, "north": 2, "west": 1},
+			"occupied": {"true": 4},
+			"part":     {"head": 8},
+		}},
+	},
+	"brewingStand": {
+		{Values: map[string]map[string]int{
+			"has_bottle_0": {"true": 1},
+			"has_bottle_1": {"true": 2},
+			"has_bottle_2": {"true": 4},
+		}},
+	},
+	"button": {
+		{Values: map[string]map[string]int{
+			"facing":  {"east": 1, "north": 4, "south": 3, "up": 5, "west": 2},
+			"powered": {"true": 8},
+		}},
+	},
+	"cactus": {
+		{Number: "age"},
+	},
+	"carpet": {
+		{Number: "color"},
+	},
+	"cauldron": {
+		{Number: "level"},
+	},
+	"chest": {
+		{Number: "facing"},
+	},
+	"cocoa": {
+		{Values: map[string]map[string]int{
+			"age":    {"1": 4, "2": 8},
+			"facing": {"east": 3, "north": 2, "west": 1},
+		}},
+	},
+	"comparator": {
+		{Values: map[string]map[string]int{
+			"facing":  {"east": 3, "north": 2, "west": 1},
+			"mode":    {"subtract": 4},
+			"powered": {"true": 8},
+		}},
+	},
+	"connectable": {
+		{If: map[string]string{"east": "false", "north": "false", "south": "false", "west": "false"}},
+	},
+	"crop": {
+		{Number: "age"},
+	},
+	"deadBush": {
+		{},
+	},
+	"default": {
+		{},
+	},
+	"dispenser": {
+		{Values: map[string]map[string]int{
+			"facing":    {"east": 5, "north": 2, "south": 3, "up": 1, "west": 4},
+			"triggered": {"true": 8},
+		}},
+	},
+	"door": {
+		{If: map[string]string{"half": "upper"}, Data: 8, Values: map[string]map[string]int{
+			"hinge":   {"right": 1},
+			"powered": {"true": 2},
+		}},
+		{If: map[string]string{"half": "lower"}, Values: map[string]map[string]int{
+			"facing": {"north": 3, "south": 1, "west": 2},
+			"open":   {"true": 4},
+		}},
+	},
+	"doublePlant": {
+		{If: map[string]string{"half": "upper", "variant": "sunflower"}, Data: 8, Number: "facing"},
+		{If: map[string]string{"facing": "east", "half": "lower"}, Number: "variant"},
+	},
+	"enchantingTable": {
+		{},
+	},
+	"endPortalFrame": {
+		{Values: map[string]map[string]int{
+			"eye":    {"true": 4},
+			"facing": {"east": 3, "north": 2, "west": 1},
+		}},
+	},
+	"farmland": {
+		{Number: "moisture"},
+	},
+	"fence": {
+		{If: map[string]string{"east": "false", "north": "false", "south": "false", "west": "false"}},
+	},
+	"fenceGate": {
+		{If: map[string]string{"in_wall": "false"}, Values: map[string]map[string]int{
+			"facing":  {"east": 3, "north": 2, "west": 1},
+			"open":    {"true": 4},
+			"powered": {"true": 8},
+		}},
+	},
+	"fire": {
+		{Number: "age"},
+	},
+	"floorSign": {
+		{Number: "rotation"},
+	},
+	"grass": {
+		{If: map[string]string{"snowy": "false"}},
+	},
+	"hopper": {
+		{Values: map[string]map[string]int{
+			"enabled": {"false": 8},
+			"facing":  {"east": 5, "north": 2, "south": 3, "west": 4},
+		}},
+	},
+	"ladder": {
+		{Number: "facing"},
+	},
+	"leaves": {
+		{Values: map[string]map[string]int{
+			"check_decay": {"true": 8},
+			"decayable":   {"false": 4},
+			"variant":     {"birch": 2, "dark_oak": 1, "jungle": 3, "spruce": 1},
+		}},
+	},
+	"lever": {
+		{Number: "facing", Values: map[string]map[string]int{
+			"powered": {"true": 8},
+		}},
+	},
+	"lilypad": {
+		{},
+	},
+	"liquid": {
+		{Number: "level"},
+	},
+	"log": {
+		{Values: map[string]map[string]int{
+			"axis":    {"none": 12, "x": 8, "z": 4},
+			"variant": {"birch": 2, "dark_oak": 1, "jungle": 3, "spruce": 1},
+		}},
+	},
+	"netherWart": {
+		{Number: "age"},
+	},
+	"noteBlock": {
+		{},
+	},
+	"piston": {
+		{Values: map[string]map[string]int{
+			"extended": {"true": 8},
+			"facing":   {"east": 5, "north": 2, "south": 3, "up": 1, "west": 4},
+		}},
+	},
+	"pistonHead": {
+		{If: map[string]string{"short": "false"}, Values: map[string]map[string]int{
+			"facing": {"east": 5, "north": 2, "south": 3, "up": 1, "west": 4},
+			"type":   {"sticky": 8},
+		}},
+	},
+	"planks": {
+		{Number: "variant"},
+	},
+	"portal": {
+		{Values: map[string]map[string]int{
+			"axis": {"x": 1, "z": 2},
+		}},
+	},
+	"poweredRail": {
+		{Number: "shape", Values: map[string]map[string]int{
+			"powered": {"true": 8},
+		}},
+	},
+	"pressurePlate": {
+		{Values: map[string]map[string]int{
+			"powered": {"true": 1},
+		}},
+	},
+	"quartzBlock": {
+		{Values: map[string]map[string]int{
+			"variant": {"chiseled": 1, "lines_x": 3, "lines_y": 2, "lines_z": 4},
+		}},
+	},
+	"rail": {
+		{Number: "shape"},
+	},
+	"redFlower": {
+		{Number: "type"},
+	},
+	"redstone": {
+		{Number: "power"},
+	},
+	"repeater": {
+		{If: map[string]string{"locked": "false"}, Values: map[string]map[string]int{
+			"delay":  {"2": 4, "3": 8, "4": 12},
+			"facing": {"east": 3, "north": 2, "west": 1},
+		}},
+	},
+	"sapling": {
+		{Number: "type", Values: map[string]map[string]int{
+			"stage": {"1": 8},
+		}},
+	},
+	"skull": {
+		{Values: map[string]map[string]int{
+			"facing": {"east": 4, "north": 2, "south": 3, "up": 1, "west": 5},
+			"nodrop": {"true": 8},
+		}},
+	},
+	"slab": {
+		{Values: map[string]map[string]int{
+			"half":    {"top": 8},
+			"variant": {"acacia": 4, "birch": 2, "brick": 4, "cobblestone": 3, "dark_oak": 5, "jungle": 3, "nether_brick": 6, "quartz": 7, "sandstone": 1, "spruce": 1, "stone_brick": 5, "wood_old": 2},
+		}},
+	},
+	"slabDouble": {
+		{Values: map[string]map[string]int{
+			"variant": {"acacia": 4, "birch": 2, "brick": 4, "cobblestone": 3, "dark_oak": 5, "jungle": 3, "nether_brick": 6, "quartz": 7, "sandstone": 1, "spruce": 1, "stone_brick": 5, "wood_old": 2},
+		}},
+	},
+	"slabDoubleSeamless": {
+		{Values: map[string]map[string]int{
+			"seamless": {"true": 8},
+			"variant":  {"acacia": 4, "birch": 2, "brick": 4, "cobblestone": 3, "dark_oak": 5, "jungle": 3, "nether_brick": 6, "quartz": 7, "sandstone": 1, "spruce": 1, "stone_brick": 5, "wood_old": 2},
+		}},
+	},
+	"snowLayer": {
+		{Data: -1, Number: "moisture"},
+	},
+	"soulSand": {
+		{},
+	},
+	"sponge": {
+		{Values: map[string]map[string]int{
+			"wet": {"true": 1},
+		}},
+	},
+	"stainedClay": {
+		{Number: "color"},
+	},
+	"stainedGlass": {
+		{Number: "color"},
+	},
+	"stainedGlassPane": {
+		{If: map[string]string{"east": "false", "north": "false", "south": "false", "west": "false"}, Number: "color"},
+	},
+	"stairs": {
+		{If: map[string]string{"shape": "straight"}, Values: map[string]map[string]int{
+			"facing": {"north": 3, "south": 2, "west": 1},
+			"half":   {"top": 4},
+		}},
+	},
+	"stone": {
+		{Number: "variant"},
+	},
+	"stonebrick": {
+		{Number: "variant"},
+	},
+	"tallGrass": {
+		{Number: "type"},
+	},
+	"torch": {
+		{Values: map[string]map[string]int{
+			"facing": {"0": 1, "1": 2, "2": 3, "3": 4, "4": 5},
+		}},
+	},
+	"trapDoor": {
+		{Values: map[string]map[string]int{
+			"facing": {"east": 3, "south": 1, "west": 2},
+			"half":   {"top": 8},
+			"open":   {"true": 4},
+		}},
+	},
+	"tripwire": {
+		{If: map[string]string{"east": "false", "north": "false", "south": "false", "west": "false"}, Values: map[string]map[string]int{
+			"attached": {"true": 4},
+			"disarmed": {"true": 8},
+			"powered":  {"true": 1},
+		}},
+	},
+	"tripwireHook": {
+		{Values: map[string]map[string]int{
+			"attached": {"true": 4},
+			"facing":   {"east": 3, "north": 2, "west": 1},
+			"powered":  {"true": 8},
+		}},
+	},
+	"vines": {
+		{Values: map[string]map[string]int{
+			"east":  {"true": 8},
+			"north": {"true": 4},
+			"south": {"true": 1},
+			"west":  {"true": 2},
+		}},
+	},
+	"wall": {
+		{If: map[string]string{"east": "false", "north": "false", "south": "false", "up": "false", "west": "false"}, Number: "variant"},
+	},
+	"wallSign": {
+		{Number: "facing"},
+	},
+	"weightedPressurePlate": {
+		{Number: "power"},
+	},
+	"wool": {
+		{Number: "color"},
+	},
+	"yellowFlower": {
+		{},
+	},
+}
+
+// blockClientStates maps each block type to the states the
+// client works out itself.
+var blockClientStates = map[string][]string{
+	"connectable":      {"north", "south", "east", "west"},
+	"door":             {"facing", "hinge", "open"},
+	"doublePlant":      {"variant", "facing"},
+	"fence":            {"north", "south", "east", "west"},
+	"fenceGate":        {"in_wall"},
+	"fire":             {"alt", "flip", "up", "north", "south", "east", "west"},
+	"grass":            {"snowy"},
+	"pistonHead":       {"short"},
+	"redstone":         {"north", "south", "east", "west"},
+	"repeater":         {"locked"},
+	"stainedGlassPane": {"north", "south", "east", "west"},
+	"stairs":           {"shape"},
+	"tripwire":         {"north", "south", "east", "west"},
+	"vines":            {"up"},
+	"wall":             {"up", "north", "south", "east", "west"},
+}
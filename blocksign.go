@@ -35,12 +35,17 @@ type signComponent struct {
 
 type SignComponent interface {
 	Update(lines [4]format.AnyComponent)
+	Lines() [4]format.AnyComponent
 }
 
 func (s *signComponent) Model() *render.Model {
 	return s.model
 }
 
+func (s *signComponent) Lines() [4]format.AnyComponent {
+	return s.lines
+}
+
 func (s *signComponent) Update(lines [4]format.AnyComponent) {
 	s.free()
 	s.lines = lines
@@ -14,10 +14,7 @@
 
 package steven
 
-import (
-	"reflect"
-	"strconv"
-)
+import "reflect"
 
 type blockSimple struct {
 	baseBlock
@@ -28,16 +25,5 @@ func (b *blockSimple) load(tag reflect.StructTag) {
 	b.cullAgainst = getBool("cullAgainst", true)
 	b.collidable = getBool("collidable", true)
 	b.renderable = getBool("renderable", true)
-	hardness, err := strconv.ParseFloat(tag.Get("hardness"), 64)
-	if err == nil {
-		b.hardness = hardness
-	}
 	b.translucent = getBool("translucent", false)
 }
-
-func (b *blockSimple) toData() int {
-	if b == b.Parent.Base {
-		return 0
-	}
-	return -1
-}
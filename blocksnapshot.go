@@ -89,7 +89,7 @@ func (bs *blocksSnapshot) init(x, y, z, w, h, d int) {
 
 	for cx := cx1; cx < cx2; cx++ {
 		for cz := cz1; cz < cz2; cz++ {
-			chunk := chunkMap[chunkPosition{cx, cz}]
+			chunk := chunkMap.chunks[chunkPosition{cx, cz}]
 			if chunk == nil {
 				continue
 			}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"image"
 	"reflect"
+
+	"github.com/thinkofdeath/steven/type/direction"
 )
 
 // Logs have 4 possible 'rotations' (more like shapes),
@@ -105,13 +107,14 @@ func (l *blockLog) ModelVariant() string {
 	return fmt.Sprintf("axis=%s", l.Axis)
 }
 
-func (l *blockLog) toData() int {
-	data := int(l.Variant)
-	if l.Second {
-		data -= 4
+func (l *blockLog) placementState(p blockPlacement) Block {
+	switch p.Face {
+	case direction.East, direction.West:
+		return l.Set("axis", axisX)
+	case direction.North, direction.South:
+		return l.Set("axis", axisZ)
 	}
-	data |= int(l.Axis) << 2
-	return data
+	return l.Set("axis", axisY)
 }
 
 type blockLeaves struct {
@@ -147,20 +150,6 @@ func (l *blockLeaves) TintImage() *image.NRGBA {
 	return foliageBiomeColors
 }
 
-func (l *blockLeaves) toData() int {
-	data := int(l.Variant)
-	if l.Second {
-		data -= 4
-	}
-	if !l.Decayable {
-		data |= 0x4
-	}
-	if l.CheckDecay {
-		data |= 0x8
-	}
-	return data
-}
-
 type blockPlanks struct {
 	baseBlock
 	Variant treeVariant `state:"variant,0-5"`
@@ -170,10 +159,6 @@ func (b *blockPlanks) ModelName() string {
 	return b.Variant.String() + "_planks"
 }
 
-func (b *blockPlanks) toData() int {
-	return int(b.Variant)
-}
-
 type blockSapling struct {
 	baseBlock
 	Variant treeVariant `state:"type,0-5"`
@@ -191,9 +176,3 @@ func (b *blockSapling) ModelName() string {
 func (b *blockSapling) ModelVariant() string {
 	return fmt.Sprintf("stage=%d", b.Stage)
 }
-
-func (b *blockSapling) toData() int {
-	data := int(b.Variant)
-	data |= b.Stage << 3
-	return data
-}
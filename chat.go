@@ -16,6 +16,7 @@ package steven
 
 import (
 	"math"
+	"strings"
 
 	"github.com/go-gl/glfw/v3.1/glfw"
 	"github.com/thinkofdeath/steven/format"
@@ -27,6 +28,7 @@ import (
 const (
 	chatHistoryLines = 10
 	maxLineWidth     = 640
+	maxCompletions   = 10
 )
 
 type ChatUI struct {
@@ -41,6 +43,15 @@ type ChatUI struct {
 	wasEnteringText bool
 	inputLine       []rune
 	cursorTick      float64
+
+	// Tab completion state. completionBase is the input line
+	// without the word being completed.
+	waitingCompletion    bool
+	completions          []string
+	completionIndex      int
+	completionBase       []rune
+	completionText       []*ui.Text
+	completionBackground *ui.Image
 }
 
 type chatLine struct {
@@ -61,6 +72,19 @@ func (c *ChatUI) init() {
 	c.inputBackground.SetDraw(false)
 	Client.scene.AddDrawable(c.inputBackground)
 	Client.scene.AddDrawable(c.input)
+
+	c.completionBackground = ui.NewImage(render.GetTexture("solid"), 0, 22, 0, 0, 0, 0, 1, 1, 0, 0, 0).Attach(ui.Bottom, ui.Left)
+	c.completionBackground.SetA(200)
+	c.completionBackground.AttachTo(c.container)
+	c.completionBackground.SetDraw(false)
+	Client.scene.AddDrawable(c.completionBackground)
+	for i := 0; i < maxCompletions; i++ {
+		txt := ui.NewText("", 0, 0, 255, 255, 255).Attach(ui.Bottom, ui.Left)
+		txt.AttachTo(c.container)
+		txt.SetDraw(false)
+		Client.scene.AddDrawable(txt)
+		c.completionText = append(c.completionText, txt)
+	}
 }
 
 func (c *ChatUI) Draw(delta float64) {
@@ -141,6 +165,7 @@ func (c *ChatUI) handleKey(w *glfw.Window, key glfw.Key, scancode int, action gl
 		// Return control back to the default
 		c.enteringText = false
 		c.inputLine = c.inputLine[:0]
+		c.clearCompletions()
 		lockMouse = true
 		w.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
 		w.SetCharCallback(nil)
@@ -150,6 +175,10 @@ func (c *ChatUI) handleKey(w *glfw.Window, key glfw.Key, scancode int, action gl
 		if len(c.inputLine) > 0 {
 			c.inputLine = c.inputLine[:len(c.inputLine)-1]
 		}
+		c.clearCompletions()
+	}
+	if key == glfw.KeyTab && action != glfw.Release {
+		c.complete()
 	}
 }
 
@@ -157,6 +186,100 @@ func (c *ChatUI) handleChar(w *glfw.Window, char rune) {
 	if len(c.inputLine) < 100 {
 		c.inputLine = append(c.inputLine, char)
 	}
+	c.clearCompletions()
+}
+
+// complete either requests completions for the current line from
+// the server or, if some have already been returned, cycles through
+// them.
+func (c *ChatUI) complete() {
+	if len(c.completions) > 0 {
+		c.completionIndex = (c.completionIndex + 1) % len(c.completions)
+		c.applyCompletion()
+		return
+	}
+	if c.waitingCompletion {
+		return
+	}
+	c.waitingCompletion = true
+	line := string(c.inputLine)
+	c.completionBase = []rune(line[:strings.LastIndex(line, " ")+1])
+
+	pos, b, _, _ := Client.targetBlock()
+	Client.network.Write(&protocol.TabComplete{
+		Text:      line,
+		HasTarget: !b.Is(Blocks.Air),
+		Target:    protocol.NewPosition(pos.X, pos.Y, pos.Z),
+	})
+}
+
+// handleCompletions is called when the server replies to a
+// completion request.
+func (c *ChatUI) handleCompletions(matches []string) {
+	if !c.waitingCompletion || !c.enteringText {
+		return
+	}
+	c.waitingCompletion = false
+	if len(matches) == 0 {
+		return
+	}
+	c.completions = matches
+	c.completionIndex = 0
+	c.applyCompletion()
+}
+
+// applyCompletion replaces the word being completed with the currently
+// selected match and updates the suggestion popup.
+func (c *ChatUI) applyCompletion() {
+	match := c.completions[c.completionIndex]
+	c.inputLine = append(c.inputLine[:0], c.completionBase...)
+	c.inputLine = append(c.inputLine, []rune(match)...)
+	if len(c.completions) == 1 {
+		c.clearCompletions()
+		return
+	}
+
+	x := 5 + render.SizeOfString(string(c.completionBase))
+	// Only show the matches around the selected one if there
+	// are too many to fit
+	start := 0
+	if c.completionIndex >= maxCompletions {
+		start = c.completionIndex - maxCompletions + 1
+	}
+	width := 0.0
+	count := 0
+	for i, txt := range c.completionText {
+		idx := start + i
+		if idx >= len(c.completions) {
+			txt.SetDraw(false)
+			continue
+		}
+		txt.Update(c.completions[idx])
+		txt.SetX(x)
+		txt.SetY(24 + 18*float64(i))
+		if idx == c.completionIndex {
+			txt.SetB(85)
+		} else {
+			txt.SetB(255)
+		}
+		txt.SetDraw(true)
+		width = math.Max(width, render.SizeOfString(c.completions[idx]))
+		count++
+	}
+	c.completionBackground.SetX(x - 2)
+	c.completionBackground.SetWidth(width + 4)
+	c.completionBackground.SetHeight(18*float64(count) + 2)
+	c.completionBackground.SetDraw(true)
+}
+
+func (c *ChatUI) clearCompletions() {
+	c.waitingCompletion = false
+	c.completions = nil
+	c.completionIndex = 0
+	for _, txt := range c.completionText {
+		txt.SetDraw(false)
+	}
+	c.completionBackground.SetDraw(false)
 }
 
 func chatColorRGB(c format.Color) (r, g, b int) {
@@ -16,6 +16,7 @@ package steven
 
 import (
 	"encoding/hex"
+	"fmt"
 	"math"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/thinkofdeath/steven/format"
 	"github.com/thinkofdeath/steven/protocol"
 	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/resource/locale"
 	"github.com/thinkofdeath/steven/type/direction"
 	"github.com/thinkofdeath/steven/type/vmath"
 	"github.com/thinkofdeath/steven/ui"
@@ -47,10 +49,15 @@ func initClient() {
 		for _, e := range Client.blockBreakers {
 			Client.entities.container.RemoveEntity(e)
 		}
+		for _, e := range Client.movingBlocks {
+			Client.entities.container.RemoveEntity(e)
+		}
 		if Client.entity != nil && Client.entityAdded {
 			Client.entities.container.RemoveEntity(Client.entity)
 		}
 		Client.playerList.free()
+		Client.worldBorder.free()
+		Client.particles.free()
 
 		Client.playerInventory.Close()
 		Client.hotbarScene.Hide()
@@ -64,32 +71,63 @@ const (
 	cameraNormal cameraMode = iota
 	cameraBehind
 	cameraFront
+	// Viewing from another entity as requested
+	// by the server
+	cameraEntity
 )
 
 type ClientState struct {
 	scene *scene.Type
 
 	cameraMode cameraMode
+	// The mode to return to once the camera is
+	// detached from another entity
+	lastCameraMode cameraMode
+	cameraTarget   int
+	// The entity the player is riding, if riding is true
+	riding    bool
+	vehicleID int
+
+	entityID int
 
 	entity      *clientEntity
 	entityAdded bool
 
-	LX, LY, LZ float64
 	X, Y, Z    float64
 	Yaw, Pitch float64
 
 	Health float64
 	Hunger float64
+	Score  int
+
+	inCombat     bool
+	deathMessage format.AnyComponent
+
+	KeyState   [keyCount]bool
+	OnGround   bool
+	isLeftDown bool
+	stepTimer  float64
+	physics    playerPhysics
+
+	isSprinting, isSneaking         bool
+	serverSprinting, serverSneaking bool
+
+	// sentViewDistance is the view distance last sent to the server
+	sentViewDistance int
 
-	VSpeed                   float64
-	KeyState                 [keyCount]bool
-	OnGround, didTouchGround bool
-	isLeftDown               bool
-	stepTimer                float64
+	GameMode   gameMode
+	HardCore   bool
+	Difficulty difficulty
+	// The world's spawn point, used by compasses
+	SpawnPosition Position
 
-	GameMode gameMode
-	isFlying bool
-	HardCore bool
+	// Abilities as set by the server
+	Invulnerable bool
+	AllowFlying  bool
+	Creative     bool
+	isFlying     bool
+	FlyingSpeed  float64
+	WalkingSpeed float64
 
 	setInitialTime             bool
 	WorldType                  worldType
@@ -99,6 +137,13 @@ type ClientState struct {
 
 	Bounds vmath.AABB
 
+	worldBorder worldBorder
+	particles   particleManager
+
+	// Statistics as last sent by the server
+	statistics   map[string]int
+	achievements achievementToasts
+
 	debug struct {
 		enabled  bool
 		position *ui.Text
@@ -122,6 +167,8 @@ type ClientState struct {
 	foodFillUI []*ui.Image
 
 	currentHotbarSlot, lastHotbarSlot int
+	itemCooldowns                     itemCooldowns
+	placePredictions                  placePredictions
 	lastHotbarItem                    *ItemStack
 	itemNameUI                        *ui.Formatted
 	itemNameTimer                     float64
@@ -141,6 +188,7 @@ type ClientState struct {
 	swingTimer              float64
 	breakEntity             BlockEntity
 	blockBreakers           map[int]BlockEntity
+	movingBlocks            []BlockEntity
 
 	delta float64
 }
@@ -151,7 +199,9 @@ func newClient() {
 			Min: mgl32.Vec3{-0.3, 0, -0.3},
 			Max: mgl32.Vec3{0.3, 1.8, 0.3},
 		},
-		scene: scene.New(true),
+		scene:        scene.New(true),
+		FlyingSpeed:  defaultFlyingSpeed,
+		WalkingSpeed: defaultWalkingSpeed,
 	}
 	Client = c
 	c.playerInventory = NewInventory(InvPlayer, 0, 45)
@@ -159,6 +209,7 @@ func newClient() {
 	c.network.init()
 	c.currentBreakingBlock = Blocks.Air.Base
 	c.blockBreakers = map[int]BlockEntity{}
+	c.statistics = map[string]int{}
 	widgets := render.GetTexture("gui/widgets")
 	icons := render.GetTexture("gui/icons")
 	// Crosshair
@@ -214,6 +265,8 @@ func newClient() {
 	c.initDebug()
 	c.playerList.init()
 	c.entities.init()
+	c.worldBorder.init(c.scene)
+	c.achievements.init(c.scene)
 
 	c.initEntity(false)
 }
@@ -250,6 +303,7 @@ type clientEntity struct {
 
 func (c *ClientState) updateWorldType(wt worldType) {
 	c.WorldType = wt
+	chunkMap.data.HasSky = wt == wtOverworld
 	switch c.WorldType {
 	case wtOverworld:
 		render.LightLevel = 0.8
@@ -313,8 +367,16 @@ func (c *ClientState) initEntity(head bool) {
 }
 
 func (c *ClientState) cycleCamera() {
+	// The server controls the camera when attached
+	if c.cameraMode == cameraEntity {
+		return
+	}
+	c.setCameraMode((c.cameraMode + 1) % 3)
+}
+
+func (c *ClientState) setCameraMode(mode cameraMode) {
 	oldMode := c.cameraMode
-	c.cameraMode = (c.cameraMode + 1) % 3
+	c.cameraMode = mode
 	if oldMode == cameraNormal || c.cameraMode == cameraNormal {
 		// Reset the entity
 		oldEntity := c.entity
@@ -330,103 +392,20 @@ func (c *ClientState) renderTick(delta float64) {
 	c.delta = delta
 	c.hotbarUI.SetX(-184 + 24 + 40*float64(c.currentHotbarSlot))
 	c.tickItemName()
+	c.itemCooldowns.tick(delta)
+	c.placePredictions.tick(delta)
+	tickItemTextures(delta)
+	c.achievements.tick(delta)
 
-	forward, yaw := c.calculateMovement()
+	c.updateSprintSneak()
 
-	c.LX, c.LY, c.LZ = c.X, c.Y, c.Z
 	lx, ly, lz := c.X, c.Y, c.Z
-
-	if chunkMap[chunkPosition{int(math.Floor(c.X)) >> 4, int(math.Floor(c.Z)) >> 4}] != nil {
-		speed := 4.317 / 60.0
-		if c.KeyState[KeySprint] {
-			speed = 5.612 / 60.0
-		}
-		if c.isFlying {
-			speed *= 2.5
-		}
-		if _, ok := chunkMap.Block(int(math.Floor(c.X)), int(math.Floor(c.Y)), int(math.Floor(c.Z))).(*blockLiquid); ok {
-			speed = 2.20 / 60.0
-			if c.KeyState[KeyJump] {
-				c.VSpeed = 0.05
-			} else {
-				c.VSpeed -= 0.005 * delta
-				if c.VSpeed < -0.05 {
-					c.VSpeed = -0.05
-				}
-			}
-		} else if !c.OnGround && !c.isFlying {
-			c.VSpeed -= 0.01 * delta
-			if c.VSpeed < -0.3 {
-				c.VSpeed = -0.3
-			}
-		} else if c.KeyState[KeyJump] {
-			if c.isFlying {
-				c.Y += speed * delta
-			} else {
-				c.VSpeed = 0.15
-			}
-		} else {
-			c.VSpeed = 0
-		}
-		if c.KeyState[KeySneak] {
-			if c.isFlying {
-				c.Y -= speed * delta
-			}
-			// TODO: Sneak animation
-		}
-		c.X += forward * math.Cos(yaw) * delta * speed
-		c.Z -= forward * math.Sin(yaw) * delta * speed
-		c.Y += c.VSpeed * delta
-	}
-
-	if !c.GameMode.NoClip() {
-		cx := c.X
-		cy := c.Y
-		cz := c.Z
-		c.Y = c.LY
-		c.Z = c.LZ
-
-		// We handle each axis separately to allow for a sliding
-		// effect when pushing up against walls.
-
-		bounds, xhit := c.checkCollisions(c.Bounds)
-		c.X = float64(bounds.Min[0] + 0.3)
-		c.LX = c.X
-
-		c.Z = cz
-		bounds, zhit := c.checkCollisions(c.Bounds)
-		c.Z = float64(bounds.Min[2] + 0.3)
-		c.LZ = c.Z
-
-		// Half block jumps
-		// Minecraft lets you 'jump' up 0.5 blocks
-		// for slabs and stairs (or smaller blocks).
-		// Currently we implement this as a teleport to the
-		// top of the block if we could move there
-		// but this isn't smooth.
-		if (xhit || zhit) && c.OnGround {
-			ox, oz := c.X, c.Z
-			c.X, c.Z = cx, cz
-			for i := 1.0 / 16.0; i <= 0.5; i += 1.0 / 16.0 {
-				mini := c.Bounds.Shift(0, float32(i), 0)
-				_, hit := c.checkCollisions(mini)
-				if !hit {
-					cy += i
-					ox, oz = c.X, c.Z
-					break
-				}
-			}
-			c.X, c.Z = ox, oz
-		}
-
-		c.Y = cy
-		bounds, yhit := c.checkCollisions(c.Bounds)
-		c.Y = float64(bounds.Min.Y())
-		if yhit {
-			c.VSpeed = 0
-		}
-
-		c.checkGround()
+	if c.riding {
+		c.followVehicle()
+	} else {
+		// Movement happens once a tick, smooth it out between
+		// ticks
+		c.X, c.Y, c.Z = c.physics.position()
 	}
 
 	c.Pitch = math.Mod(c.Pitch, math.Pi*2)
@@ -443,9 +422,12 @@ func (c *ClientState) renderTick(delta float64) {
 	c.entity.SetTargetPosition(c.X-ox, c.Y, c.Z-oz)
 	c.entity.SetTargetYaw(-c.Yaw)
 	c.entity.SetTargetPitch(-c.Pitch - math.Pi)
-	c.entity.walking = c.X != lx || c.Y != ly || c.Z != lz
+	c.entity.walking = !c.riding && (c.X != lx || c.Y != ly || c.Z != lz)
+	c.entity.SetSneaking(c.isSneaking)
+
+	c.worldBorder.tick(c.X, c.Y, c.Z)
 
-	audio.SetListenerPosition(float32(c.X), float32(c.Y+playerHeight), float32(c.Z))
+	audio.SetListenerPosition(float32(c.X), float32(c.Y+c.eyeHeight()), float32(c.Z))
 	view := c.viewVector()
 	audio.SetListenerDirection(view.X(), view.Y(), view.Z())
 	audio.SetGlobalVolume(100)
@@ -477,7 +459,9 @@ func (c *ClientState) renderTick(delta float64) {
 
 	c.playerList.render(delta)
 	c.entities.tick()
+	c.removeMovedBlocks()
 	c.copyToCamera()
+	c.particles.tick(delta)
 
 	if c.TickTime {
 		c.TargetWorldTime += delta / 3.0
@@ -537,14 +521,6 @@ func (c *ClientState) armTick() {
 			c.swingTimer = 15
 			c.entity.SwingArm()
 			c.network.Write(&protocol.ArmSwing{})
-			e := c.targetEntity()
-			if ne, ok := e.(NetworkComponent); ok {
-				c.network.Write(&protocol.UseEntity{
-					TargetID: protocol.VarInt(ne.EntityID()),
-					Type:     1, // Attack
-				})
-				return
-			}
 			if !b.Is(Blocks.Air) {
 				name, vol, pitch := b.DigSound()
 				PlaySoundAt(name, vol, pitch, pos.Vec())
@@ -610,6 +586,20 @@ func (c *ClientState) armTick() {
 	}
 }
 
+// removeMovedBlocks removes the blocks moved by pistons once
+// they have finished moving.
+func (c *ClientState) removeMovedBlocks() {
+	blocks := c.movingBlocks[:0]
+	for _, e := range c.movingBlocks {
+		if e.(MovingBlockComponent).Done() {
+			c.entities.container.RemoveEntity(e)
+			continue
+		}
+		blocks = append(blocks, e)
+	}
+	c.movingBlocks = blocks
+}
+
 func (c *ClientState) killBreakEntity() {
 	if c.breakEntity != nil {
 		c.entities.container.RemoveEntity(c.breakEntity)
@@ -636,7 +626,11 @@ func (c *ClientState) UpdateHealth(health float64) {
 		}
 	}
 	if health == 0.0 {
-		setScreen(newRespawnScreen())
+		if _, ok := currentScreen.(*respawnScreen); !ok {
+			setScreen(newRespawnScreen())
+		}
+	} else {
+		c.deathMessage = format.AnyComponent{}
 	}
 }
 
@@ -664,17 +658,36 @@ func (c *ClientState) UpdateHunger(hunger float64) {
 
 func (c *ClientState) MouseAction(button glfw.MouseButton, down bool) {
 	if button == glfw.MouseButtonLeft {
+		// Clicking on an entity attacks it instead of
+		// breaking the block behind it
+		if down {
+			if e, _ := c.targetEntity(); c.attackEntity(e) {
+				return
+			}
+		}
 		c.isLeftDown = down
 	} else if button == glfw.MouseButtonRight && down {
-		e := c.targetEntity()
+		e, hit := c.targetEntity()
 		if ne, ok := e.(NetworkComponent); ok {
+			// Entities such as armor stands need to know where
+			// they were clicked
+			c.network.Write(&protocol.UseEntity{
+				TargetID: protocol.VarInt(ne.EntityID()),
+				Type:     2, // Interact at
+				TargetX:  hit.X(),
+				TargetY:  hit.Y(),
+				TargetZ:  hit.Z(),
+				Hand:     0,
+			})
 			c.network.Write(&protocol.UseEntity{
 				TargetID: protocol.VarInt(ne.EntityID()),
 				Type:     0, // Interact
+				Hand:     0,
 			})
 			return
 		}
-		if c.playerInventory.Items[c.currentHotbarSlot+invPlayerHotbarOffset] != nil {
+		// Items that are cooling down can't be used
+		if item := c.playerInventory.Items[c.currentHotbarSlot+invPlayerHotbarOffset]; item != nil && !c.itemCooldowns.Active(int(item.rawID)) {
 			c.network.Write(&protocol.UseItem{
 				Hand: 0,
 			})
@@ -693,6 +706,7 @@ func (c *ClientState) MouseAction(button glfw.MouseButton, down bool) {
 			CursorY:  byte(cur.Y() * 16),
 			CursorZ:  byte(cur.Z() * 16),
 		})
+		c.predictPlacement(pos, b, face, cur)
 	}
 }
 
@@ -707,7 +721,45 @@ func directionToProtocol(d direction.Type) byte {
 	}
 }
 
-func (c *ClientState) targetEntity() (e Entity) {
+func directionFromProtocol(d byte) direction.Type {
+	switch d {
+	case 0:
+		return direction.Down
+	case 1:
+		return direction.Up
+	default:
+		return direction.Type(d)
+	}
+}
+
+// attackEntity attacks the passed entity if it's a network
+// entity. The hit is shown straight away instead of waiting
+// for the server.
+func (c *ClientState) attackEntity(e Entity) bool {
+	ne, ok := e.(NetworkComponent)
+	if !ok {
+		return false
+	}
+	c.entity.SwingArm()
+	c.network.Write(&protocol.ArmSwing{})
+	c.network.Write(&protocol.UseEntity{
+		TargetID: protocol.VarInt(ne.EntityID()),
+		Type:     1, // Attack
+	})
+	if h, ok := e.(HurtComponent); ok {
+		h.Hurt()
+	}
+	// Same rules as vanilla for a critical hit
+	if c.physics.motionY < 0 && !c.OnGround && !c.riding && !c.physics.inWater {
+		c.particles.spawnCrits(e)
+	}
+	return true
+}
+
+// targetEntity returns the entity the player is looking at
+// and the point the view hits it at relative to the entity's
+// position.
+func (c *ClientState) targetEntity() (e Entity, hit mgl32.Vec3) {
 	s := mgl32.Vec3{float32(render.Camera.X), float32(render.Camera.Y), float32(render.Camera.Z)}
 	d := c.viewVector()
 
@@ -723,8 +775,9 @@ func (c *ClientState) targetEntity() (e Entity) {
 				}
 				ex, ey, ez := ee.(PositionComponent).Position()
 				bo := ee.(SizeComponent).Bounds().Shift(float32(ex), float32(ey), float32(ez))
-				if _, ok := bo.IntersectsLine(s, d); ok {
+				if at, ok := bo.IntersectsLine(s, d); ok {
 					e = ee
+					hit = at.Sub(mgl32.Vec3{float32(ex), float32(ey), float32(ez)})
 					return false
 				}
 			}
@@ -922,43 +975,61 @@ func (c *ClientState) highlightTarget() {
 	}
 }
 
-func (c *ClientState) checkGround() {
-	ground := vmath.AABB{
-		Min: mgl32.Vec3{-0.3, -0.05, -0.3},
-		Max: mgl32.Vec3{0.3, 0.0, 0.3},
+// movementInput returns how the player wants to move relative
+// to the direction they are facing.
+func (c *ClientState) movementInput() (forward, strafe float64) {
+	if c.cameraMode == cameraEntity {
+		return 0, 0
 	}
-	prev := c.OnGround
-	_, c.OnGround = c.checkCollisions(ground)
-	if !prev && c.OnGround {
-		c.didTouchGround = true
+	if c.KeyState[KeyForward] {
+		forward++
 	}
-}
-
-func (c *ClientState) calculateMovement() (float64, float64) {
-	forward := 0.0
-	yaw := c.Yaw - math.Pi/2
-	if c.KeyState[KeyForward] || c.KeyState[KeyBackwards] {
-		forward = 1
-		if c.KeyState[KeyBackwards] {
-			yaw += math.Pi
-		}
+	if c.KeyState[KeyBackwards] {
+		forward--
 	}
-	change := 0.0
 	if c.KeyState[KeyLeft] {
-		change = (math.Pi / 2) / (math.Abs(forward) + 1)
+		strafe++
 	}
 	if c.KeyState[KeyRight] {
-		change = -(math.Pi / 2) / (math.Abs(forward) + 1)
+		strafe--
 	}
-	if c.KeyState[KeyRight] || c.KeyState[KeyLeft] {
-		forward = 1
+	if c.sneakHeld() {
+		forward *= 0.3
+		strafe *= 0.3
 	}
-	if c.KeyState[KeyBackwards] {
-		yaw -= change
-	} else {
-		yaw += change
+	return forward, strafe
+}
+
+// sneakHeld returns whether the player is sneaking, which
+// includes flying down.
+func (c *ClientState) sneakHeld() bool {
+	return c.isSneaking || (c.isFlying && c.KeyState[KeySneak])
+}
+
+// physicsTick moves the player by a single tick.
+func (c *ClientState) physicsTick() {
+	forward, strafe := c.movementInput()
+	c.physics.tick(physicsInput{
+		forward:   forward,
+		strafe:    strafe,
+		yaw:       -c.Yaw,
+		jump:      c.KeyState[KeyJump],
+		sneak:     c.sneakHeld(),
+		sprinting: c.isSprinting,
+		flying:    c.isFlying,
+		noClip:    c.GameMode.NoClip(),
+		walkSpeed: c.WalkingSpeed,
+		flySpeed:  c.FlyingSpeed,
+	}, &c.worldBorder)
+	c.OnGround = c.physics.onGround
+	if c.physics.collidedHorizontally {
+		c.setSprinting(false)
+	}
+	// Landing stops flying for anyone that can
+	// touch the ground
+	if c.OnGround && c.isFlying && !c.GameMode.NoClip() {
+		c.setFlying(false)
 	}
-	return forward, yaw
 }
 
 func (c *ClientState) facingDirection() direction.Type {
@@ -979,48 +1050,51 @@ func (c *ClientState) viewVector() mgl32.Vec3 {
 	}
 }
 
-func (c *ClientState) checkCollisions(bounds vmath.AABB) (vmath.AABB, bool) {
-	bounds = bounds.Shift(float32(c.X), float32(c.Y), float32(c.Z))
-
-	dir := mgl32.Vec3{
-		-float32(c.LX - c.X),
-		-float32(c.LY - c.Y),
-		-float32(c.LZ - c.Z),
-	}
-
-	minX, minY, minZ := int(bounds.Min.X()-1), int(bounds.Min.Y()-1), int(bounds.Min.Z()-1)
-	maxX, maxY, maxZ := int(bounds.Max.X()+1), int(bounds.Max.Y()+1), int(bounds.Max.Z()+1)
-
-	hit := false
-	for y := minY; y < maxY; y++ {
-		for z := minZ; z < maxZ; z++ {
-			for x := minX; x < maxX; x++ {
-				b := chunkMap.Block(x, y, z)
-
-				if b.Collidable() {
-					for _, bb := range b.CollisionBounds() {
-						bb = bb.Shift(float32(x), float32(y), float32(z))
-						if bb.Intersects(bounds) {
-							bounds = bounds.MoveOutOf(bb, dir)
-							hit = true
-						}
-					}
-				}
-			}
+// setCameraEntity attaches the camera to the entity with the
+// passed id. Using the player's own id returns the camera to
+// the player.
+func (c *ClientState) setCameraEntity(id int) {
+	if id == c.entityID {
+		if c.cameraMode == cameraEntity {
+			c.setCameraMode(c.lastCameraMode)
 		}
+		return
+	}
+	if c.cameraMode != cameraEntity {
+		c.lastCameraMode = c.cameraMode
+		c.setCameraMode(cameraEntity)
+	}
+	c.cameraTarget = id
+	// Stop any movement that was happening when
+	// the camera was attached
+	for i := range c.KeyState {
+		c.KeyState[i] = false
 	}
-	return bounds, hit
 }
 
 func (c *ClientState) copyToCamera() {
+	if c.cameraMode == cameraEntity {
+		e, ok := c.entities.entities[c.cameraTarget]
+		if !ok {
+			// Entity went away, return to the player
+			c.setCameraEntity(c.entityID)
+		} else if c.copyEntityToCamera(e) {
+			return
+		}
+	}
 	x, y, z := c.entity.Position()
 
 	ox := math.Cos(-c.entity.Yaw()-math.Pi/2) * 0.25
 	oz := -math.Sin(-c.entity.Yaw()-math.Pi/2) * 0.25
 	x += ox
 	z += oz
+	// Follow the vehicle directly so that the camera doesn't
+	// lag behind it
+	if sx, sy, sz, ok := c.vehicleSeat(); c.riding && ok {
+		x, y, z = sx, sy, sz
+	}
 	render.Camera.X = x
-	render.Camera.Y = y + playerHeight
+	render.Camera.Y = y + c.eyeHeight()
 	render.Camera.Z = z
 	render.Camera.Yaw = c.Yaw
 	render.Camera.Pitch = c.Pitch
@@ -1038,6 +1112,76 @@ func (c *ClientState) copyToCamera() {
 	}
 }
 
+// copyEntityToCamera positions the camera at the eyes of the
+// passed entity. Entities without a position can't be viewed
+// from and return false.
+func (c *ClientState) copyEntityToCamera(e Entity) bool {
+	p, ok := e.(PositionComponent)
+	if !ok {
+		return false
+	}
+	// The position and rotation are already interpolated
+	// by the entity systems
+	x, y, z := p.Position()
+	eyeHeight := playerHeight
+	if s, ok := e.(SizeComponent); ok {
+		eyeHeight = float64(s.Bounds().Max.Y()) * 0.85
+	}
+	render.Camera.X = x
+	render.Camera.Y = y + eyeHeight
+	render.Camera.Z = z
+	if r, ok := e.(RotationComponent); ok {
+		render.Camera.Yaw = -r.Yaw()
+		render.Camera.Pitch = -r.Pitch() + math.Pi
+	}
+	return true
+}
+
+// mainHandRight is the ClientSettings value for a right handed
+// player.
+const mainHandRight = 1
+
+// sendSettings tells the server the client's current settings.
+func (c *ClientState) sendSettings() {
+	viewDistance := render.ViewDistanceChunks()
+	c.sentViewDistance = viewDistance
+	if viewDistance > 0xFF {
+		viewDistance = 0xFF
+	}
+	c.network.Write(&protocol.ClientSettings{
+		Locale:             locale.Current(),
+		ViewDistance:       byte(viewDistance),
+		ChatColors:         true,
+		DisplayedSkinParts: 0x7F,
+		MainHand:           mainHandRight,
+	})
+}
+
+// updateViewDistance tells the server when the view distance
+// changes and unloads the chunks (and their block entities) that
+// are outside of it.
+func (c *ClientState) updateViewDistance() {
+	if render.ViewDistanceChunks() != c.sentViewDistance {
+		c.sendSettings()
+	}
+	for pos, ch := range chunkMap.chunks {
+		if c.inViewDistance(pos) {
+			ch.show()
+		} else {
+			chunkMap.removeChunk(pos.X, pos.Z)
+		}
+	}
+}
+
+// inViewDistance returns whether the chunk is close enough to the
+// player to be drawn.
+func (c *ClientState) inViewDistance(pos chunkPosition) bool {
+	viewDistance := render.ViewDistanceChunks()
+	cx := int(math.Floor(c.X)) >> 4
+	cz := int(math.Floor(c.Z)) >> 4
+	return abs(pos.X-cx) <= viewDistance+1 && abs(pos.Z-cz) <= viewDistance+1
+}
+
 func (c *ClientState) tick() {
 	// Now you may be wondering why we have to spam movement
 	// packets (any of the Player* move/look packets) 20 times
@@ -1053,23 +1197,36 @@ func (c *ClientState) tick() {
 	// what did you expect?
 	// TODO(Think) Use the smaller packets when possible
 
-	// Force the server to know when touched the ground
-	// otherwise if it happens between ticks the server
-	// will think we are flying.
-	onGround := c.OnGround
-	if c.didTouchGround {
-		c.didTouchGround = false
-		onGround = true
+	c.updateViewDistance()
+
+	if c.riding {
+		// The server moves us with the vehicle so only the
+		// rotation and input is sent
+		c.network.Write(&protocol.PlayerLook{
+			Yaw:      float32(-c.Yaw * (180 / math.Pi)),
+			Pitch:    float32((-c.Pitch - math.Pi) * (180 / math.Pi)),
+			OnGround: false,
+		})
+		c.network.Write(c.steerVehicle())
+		return
+	}
+
+	c.sendSprintSneak()
+
+	// Don't move whilst the chunk the player is in hasn't
+	// loaded yet
+	if chunkMap.chunks[chunkPosition{int(math.Floor(c.physics.x)) >> 4, int(math.Floor(c.physics.z)) >> 4}] != nil {
+		c.physicsTick()
 	}
 
 	if c.Health > 0 {
 		c.network.Write(&protocol.PlayerPositionLook{
-			X:        c.X,
-			Y:        c.Y,
-			Z:        c.Z,
+			X:        c.physics.x,
+			Y:        c.physics.y,
+			Z:        c.physics.z,
 			Yaw:      float32(-c.Yaw * (180 / math.Pi)),
 			Pitch:    float32((-c.Pitch - math.Pi) * (180 / math.Pi)),
-			OnGround: onGround,
+			OnGround: c.physics.onGround,
 		})
 	}
 }
@@ -1083,20 +1240,236 @@ const (
 	gmSpecator
 )
 
-func (g gameMode) Fly() bool {
+func (g gameMode) NoClip() bool {
 	switch g {
-	case gmCreative, gmSpecator:
+	case gmSpecator:
 		return true
 	}
 	return false
 }
 
-func (g gameMode) NoClip() bool {
-	switch g {
-	case gmSpecator:
-		return true
+type difficulty int
+
+const (
+	difficultyPeaceful difficulty = iota
+	difficultyEasy
+	difficultyNormal
+	difficultyHard
+)
+
+func (d difficulty) String() string {
+	switch d {
+	case difficultyPeaceful:
+		return "Peaceful"
+	case difficultyEasy:
+		return "Easy"
+	case difficultyNormal:
+		return "Normal"
+	case difficultyHard:
+		return "Hard"
 	}
-	return false
+	return fmt.Sprintf("difficulty(%d)", int(d))
+}
+
+// The speeds vanilla uses when the server doesn't
+// change them.
+const (
+	defaultFlyingSpeed  = 0.05
+	defaultWalkingSpeed = 0.1
+)
+
+type abilityFlag byte
+
+const (
+	abilityInvulnerable abilityFlag = 1 << iota
+	abilityFlying
+	abilityAllowFlying
+	abilityCreative
+)
+
+// setFlying changes whether the player is flying and informs
+// the server of the change.
+func (c *ClientState) setFlying(flying bool) {
+	if c.isFlying == flying {
+		return
+	}
+	c.isFlying = flying
+	var flags abilityFlag
+	if c.Invulnerable {
+		flags |= abilityInvulnerable
+	}
+	if c.isFlying {
+		flags |= abilityFlying
+	}
+	if c.AllowFlying {
+		flags |= abilityAllowFlying
+	}
+	if c.Creative {
+		flags |= abilityCreative
+	}
+	c.network.Write(&protocol.ClientAbilities{
+		Flags:        byte(flags),
+		FlyingSpeed:  float32(c.FlyingSpeed),
+		WalkingSpeed: float32(c.WalkingSpeed),
+	})
+}
+
+// setVehicle changes the entity the player is riding. An
+// id of -1 dismounts the player.
+func (c *ClientState) setVehicle(id int) {
+	if id == -1 {
+		if c.riding {
+			// Continue from the seat instead of where the
+			// player mounted the vehicle
+			c.physics.teleport(c.X, c.Y, c.Z)
+			c.physics.motionX, c.physics.motionY, c.physics.motionZ = 0, 0, 0
+		}
+		c.riding = false
+		c.vehicleID = -1
+		return
+	}
+	c.riding = true
+	c.vehicleID = id
+	c.isFlying = false
+}
+
+// vehicleSeat returns the position the player sits at on
+// the vehicle they are riding.
+func (c *ClientState) vehicleSeat() (x, y, z float64, ok bool) {
+	e, ok := c.entities.entities[c.vehicleID]
+	if !ok {
+		return 0, 0, 0, false
+	}
+	p, ok := e.(PositionComponent)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	x, y, z = p.Position()
+	height := 0.0
+	if s, ok := e.(SizeComponent); ok {
+		height = float64(s.Bounds().Max.Y())
+	}
+	// Vanilla's default mount offset for the vehicle
+	// and the player's offset when riding
+	y += height*0.75 - 0.35
+	return x, y, z, true
+}
+
+// followVehicle moves the player with the vehicle they are
+// riding. The player is dismounted if the vehicle no longer
+// exists.
+func (c *ClientState) followVehicle() {
+	x, y, z, ok := c.vehicleSeat()
+	if !ok {
+		c.setVehicle(-1)
+		return
+	}
+	c.X, c.Y, c.Z = x, y, z
+	// Dismounting continues from the seat
+	c.physics.teleport(x, y, z)
+	c.physics.motionX, c.physics.motionY, c.physics.motionZ = 0, 0, 0
+	c.OnGround = false
+}
+
+type steerFlag byte
+
+const (
+	steerJump steerFlag = 1 << iota
+	steerUnmount
+)
+
+// steerVehicle returns the packet to send the current movement
+// input to the server whilst riding.
+func (c *ClientState) steerVehicle() *protocol.SteerVehicle {
+	var sideways, forward float32
+	if c.KeyState[KeyForward] {
+		forward++
+	}
+	if c.KeyState[KeyBackwards] {
+		forward--
+	}
+	if c.KeyState[KeyLeft] {
+		sideways++
+	}
+	if c.KeyState[KeyRight] {
+		sideways--
+	}
+	var flags steerFlag
+	if c.KeyState[KeyJump] {
+		flags |= steerJump
+	}
+	if c.KeyState[KeySneak] {
+		// Sneaking dismounts the player
+		flags |= steerUnmount
+		sideways *= 0.3
+		forward *= 0.3
+	}
+	return &protocol.SteerVehicle{
+		Sideways: sideways,
+		Forward:  forward,
+		Flags:    byte(flags),
+	}
+}
+
+// updateSprintSneak updates the sprinting and sneaking state
+// from the player's input.
+func (c *ClientState) updateSprintSneak() {
+	c.isSneaking = c.KeyState[KeySneak] && !c.isFlying && !c.riding && c.cameraMode != cameraEntity
+	if c.KeyState[KeySprint] && c.KeyState[KeyForward] {
+		c.setSprinting(true)
+	}
+	if !c.KeyState[KeyForward] || c.isSneaking || !c.canSprint() {
+		c.setSprinting(false)
+	}
+}
+
+// canSprint returns whether the player has enough food to
+// sprint. Players that can fly aren't limited by their hunger.
+func (c *ClientState) canSprint() bool {
+	return !c.riding && (c.Hunger > 6 || c.AllowFlying)
+}
+
+func (c *ClientState) setSprinting(sprinting bool) {
+	if sprinting && (c.isSneaking || !c.canSprint()) {
+		return
+	}
+	c.isSprinting = sprinting
+}
+
+// sendSprintSneak informs the server about any changes to the
+// player's sprinting or sneaking state.
+func (c *ClientState) sendSprintSneak() {
+	send := func(action int) {
+		c.network.Write(&protocol.PlayerAction{
+			EntityID: protocol.VarInt(c.entityID),
+			ActionID: protocol.VarInt(action),
+		})
+	}
+	if c.isSneaking != c.serverSneaking {
+		c.serverSneaking = c.isSneaking
+		if c.isSneaking {
+			send(0) // Start sneaking
+		} else {
+			send(1) // Stop sneaking
+		}
+	}
+	if c.isSprinting != c.serverSprinting {
+		c.serverSprinting = c.isSprinting
+		if c.isSprinting {
+			send(3) // Start sprinting
+		} else {
+			send(4) // Stop sprinting
+		}
+	}
+}
+
+// eyeHeight returns the height of the player's eyes above
+// their feet. Sneaking lowers the camera slightly.
+func (c *ClientState) eyeHeight() float64 {
+	if c.isSneaking {
+		return playerHeight - 0.08
+	}
+	return playerHeight
 }
 
 type teleportFlag byte
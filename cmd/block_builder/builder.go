@@ -0,0 +1,239 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// block_builder generates the block registry from a json file.
+//
+// The file contains two objects. "blocks" is the list of block sets
+// in id order. Each entry has the set's field name ("name") and
+// optionally its block type ("type", "default" if missing). Every
+// other key is passed to the block as a struct tag (e.g. "mc",
+// "hardness", "cullAgainst").
+//
+// "types" maps each block type to the rules used to work out the
+// legacy data value of its states. The first rule whose "if" states
+// all match is used, the value is
+//
+//     data + number | values[state][value]...
+//
+// where "number" names a state whose integer value is added. States
+// that don't match any rule have no data value.
+//
+// "clientStates" lists the states of each block type that the client
+// works out from the neighbouring blocks instead of them being saved
+// (e.g. fence connections).
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type blockData struct {
+	Types        map[string][]dataRule `json:"types"`
+	ClientStates map[string][]string   `json:"clientStates"`
+	Blocks       []map[string]string   `json:"blocks"`
+}
+
+type dataRule struct {
+	If     map[string]string         `json:"if"`
+	Data   int                       `json:"data"`
+	Number string                    `json:"number"`
+	Values map[string]map[string]int `json:"values"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Println("Missing target")
+		os.Exit(4)
+	}
+	input := os.Args[1]
+
+	f, err := os.Open(input)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var data blockData
+	err = json.NewDecoder(f).Decode(&data)
+	f.Close()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(data.Blocks) > 0x100 {
+		log.Fatalf("too many blocks (%d), the max is 256", len(data.Blocks))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Generated by block_builder\n")
+	buf.WriteString("// Do not edit\n\n")
+	buf.WriteString("package steven\n\n")
+
+	buf.WriteString("// Valid blocks.\n")
+	buf.WriteString("var Blocks = struct {\n")
+	seen := map[string]bool{}
+	for _, b := range data.Blocks {
+		name := b["name"]
+		if name == "" {
+			log.Fatalf("block missing a name: %v", b)
+		}
+		if seen[name] {
+			log.Fatalf("duplicate block %s", name)
+		}
+		seen[name] = true
+		ty := b["type"]
+		if ty == "" {
+			ty = "default"
+		}
+		if _, ok := data.Types[ty]; !ok {
+			log.Fatalf("block %s has an unknown type %s", name, ty)
+		}
+		fmt.Fprintf(&buf, "%s *BlockSet", name)
+		if tag := blockTag(b); tag != "" {
+			fmt.Fprintf(&buf, " `%s`", tag)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}{}\n\n")
+
+	buf.WriteString("// blockDataRules maps each block type to the rules used to\n")
+	buf.WriteString("// find the legacy data value of its states.\n")
+	buf.WriteString("var blockDataRules = map[string][]blockDataRule{\n")
+	for _, ty := range sortedKeys(data.Types) {
+		fmt.Fprintf(&buf, "%q: {\n", ty)
+		for _, r := range data.Types[ty] {
+			writeRule(&buf, r)
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// blockClientStates maps each block type to the states the\n")
+	buf.WriteString("// client works out itself.\n")
+	buf.WriteString("var blockClientStates = map[string][]string{\n")
+	for _, ty := range sortedKeys(data.ClientStates) {
+		if _, ok := data.Types[ty]; !ok {
+			log.Fatalf("client states for unknown type %s", ty)
+		}
+		fmt.Fprintf(&buf, "%q: {", ty)
+		for _, s := range data.ClientStates[ty] {
+			fmt.Fprintf(&buf, "%q,", s)
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Println(buf.String())
+		log.Fatalf("format error: %s", err)
+	}
+
+	o, err := os.Create(input[:len(input)-len(filepath.Ext(input))] + "_gen.go")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer o.Close()
+	o.Write(b)
+}
+
+// blockTag returns the struct tag for the block. The type and
+// name come first to keep the common tags easy to find.
+func blockTag(b map[string]string) string {
+	var buf bytes.Buffer
+	write := func(k string) {
+		if buf.Len() > 0 {
+			buf.WriteRune(' ')
+		}
+		fmt.Fprintf(&buf, "%s:%q", k, b[k])
+	}
+	for _, k := range []string{"type", "mc"} {
+		if _, ok := b[k]; ok {
+			write(k)
+		}
+	}
+	for _, k := range sortedKeys(b) {
+		if k == "name" || k == "type" || k == "mc" {
+			continue
+		}
+		write(k)
+	}
+	return buf.String()
+}
+
+func writeRule(buf *bytes.Buffer, r dataRule) {
+	buf.WriteString("{")
+	if len(r.If) > 0 {
+		buf.WriteString("If: map[string]string{")
+		for _, k := range sortedKeys(r.If) {
+			fmt.Fprintf(buf, "%q: %q,", k, r.If[k])
+		}
+		buf.WriteString("},")
+	}
+	if r.Data != 0 {
+		fmt.Fprintf(buf, "Data: %d,", r.Data)
+	}
+	if r.Number != "" {
+		fmt.Fprintf(buf, "Number: %q,", r.Number)
+	}
+	if len(r.Values) > 0 {
+		buf.WriteString("Values: map[string]map[string]int{\n")
+		for _, k := range sortedKeys(r.Values) {
+			fmt.Fprintf(buf, "%q: {", k)
+			vals := r.Values[k]
+			for _, v := range sortedKeys(vals) {
+				fmt.Fprintf(buf, "%q: %d,", v, vals[v])
+			}
+			buf.WriteString("},\n")
+		}
+		buf.WriteString("},")
+	}
+	buf.WriteString("},\n")
+}
+
+// sortedKeys returns the keys of the passed map in order so that
+// the output is the same every time.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]string:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]int:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]map[string]int:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string][]dataRule:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string][]string:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	default:
+		panic(fmt.Sprintf("unsupported map %T", m))
+	}
+	sort.Strings(keys)
+	return keys
+}
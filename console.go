@@ -15,6 +15,8 @@
 package steven
 
 import (
+	"strings"
+
 	"github.com/go-gl/glfw/v3.1/glfw"
 	"github.com/thinkofdeath/steven/console"
 	"github.com/thinkofdeath/steven/format"
@@ -45,6 +47,10 @@ type consoleScreen struct {
 
 	input      string
 	cursorTick float64
+
+	completions     []string
+	completionIndex int
+	completionBase  string
 }
 
 func (cs *consoleScreen) init() {
@@ -81,8 +87,13 @@ func (cs *consoleScreen) onKey(w *glfw.Window, key glfw.Key, scancode int, actio
 		if len(cs.input) > 0 {
 			cs.input = cs.input[:len(cs.input)-1]
 		}
+		cs.completions = nil
+	}
+	if key == glfw.KeyTab && action != glfw.Release {
+		cs.complete()
 	}
 	if key == glfw.KeyEnter && action == glfw.Release {
+		cs.completions = nil
 		console.Component(format.
 			Build("> ").
 			Color(format.Yellow).
@@ -107,6 +118,34 @@ func (cs *consoleScreen) onKey(w *glfw.Window, key glfw.Key, scancode int, actio
 
 func (cs *consoleScreen) onChar(w *glfw.Window, char rune) {
 	cs.input += string(char)
+	cs.completions = nil
+}
+
+// complete completes the last word of the input from the
+// registered commands and cvars, cycling through the matches
+// on each press.
+func (cs *consoleScreen) complete() {
+	if len(cs.completions) > 0 {
+		cs.completionIndex = (cs.completionIndex + 1) % len(cs.completions)
+		cs.input = cs.completionBase + cs.completions[cs.completionIndex]
+		return
+	}
+	matches := console.Complete(cs.input)
+	if len(matches) == 0 {
+		return
+	}
+	cs.completionBase = cs.input[:strings.LastIndex(cs.input, " ")+1]
+	cs.input = cs.completionBase + matches[0]
+	if len(matches) == 1 {
+		return
+	}
+	cs.completions = matches
+	cs.completionIndex = 0
+	console.Component(format.
+		Build(strings.Join(matches, ", ")).
+		Color(format.Gray).
+		Create(),
+	)
 }
 
 func (cs *consoleScreen) tick(delta float64) {
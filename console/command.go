@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -230,6 +231,62 @@ func (r *registry) exec(node *commandNode, args []string, vals ...reflect.Value)
 	return nil, vals, err
 }
 
+// Complete returns the possible values for the last argument
+// of the passed command line. Only sub-commands (including cvar
+// names) can be completed, arguments handled by a type are skipped.
+func Complete(cmd string) []string {
+	return defaultRegistry.Complete(cmd)
+}
+
+func (r *registry) Complete(cmd string) []string {
+	if r.root == nil {
+		return nil
+	}
+	parts := quotedStringRegex.FindAllString(cmd, -1)
+	for i, p := range parts {
+		if strings.HasPrefix(p, `"`) && strings.HasSuffix(p, `"`) {
+			parts[i] = p[1 : len(p)-1]
+		}
+	}
+	// A trailing space means the user has started on a
+	// new argument
+	partial := ""
+	if len(parts) > 0 && !strings.HasSuffix(cmd, " ") {
+		partial = strings.ToLower(parts[len(parts)-1])
+		parts = parts[:len(parts)-1]
+	}
+
+	nodes := []*commandNode{r.root}
+	for _, part := range parts {
+		var next []*commandNode
+		for _, node := range nodes {
+			for _, info := range node.types {
+				if _, err := info.handler.ParseType(part, info.data); err == nil {
+					next = append(next, info.node)
+				}
+			}
+			if cn, ok := node.childNodes[strings.ToLower(part)]; ok {
+				next = append(next, cn)
+			}
+		}
+		nodes = next
+	}
+
+	found := map[string]struct{}{}
+	var out []string
+	for _, node := range nodes {
+		for name := range node.childNodes {
+			if _, ok := found[name]; ok || !strings.HasPrefix(name, partial) {
+				continue
+			}
+			found[name] = struct{}{}
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
 type commandNode struct {
 	childNodes map[string]*commandNode
 	types      []typeInfo
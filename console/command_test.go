@@ -165,6 +165,32 @@ func TestCommandPanic(t *testing.T) {
 	}
 }
 
+func TestComplete(t *testing.T) {
+	r := registry{}
+	r.Register("hello world", func() {})
+	r.Register("hello wide", func() {})
+	r.Register("help", func() {})
+	r.Register("cl_test %", func(v int) {})
+	r.Register("cl_test % set", func(v int) {})
+
+	checkCompletions(t, r.Complete("he"), "hello", "help")
+	checkCompletions(t, r.Complete("hello "), "wide", "world")
+	checkCompletions(t, r.Complete("hello wo"), "world")
+	checkCompletions(t, r.Complete("cl_test 5 s"), "set")
+	checkCompletions(t, r.Complete("missing "))
+}
+
+func checkCompletions(t *testing.T, got []string, expected ...string) {
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v got %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("expected %v got %v", expected, got)
+		}
+	}
+}
+
 func checkError(t *testing.T, err error) {
 	if err != nil {
 		t.Fatal(err)
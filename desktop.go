@@ -160,6 +160,15 @@ func onMouseClick(w *glfw.Window, button glfw.MouseButton, action glfw.Action, m
 		currentScreen.click(action == glfw.Press, xpos*(float64(fw)/float64(width)), ypos*(float64(fh)/float64(height)), fw, fh)
 		return
 	}
+	if Client.GameMode == gmSpecator && Client.playerList.scene.IsVisible() {
+		if button == glfw.MouseButtonLeft && action == glfw.Release {
+			width, height := w.GetSize()
+			xpos, ypos := w.GetCursorPos()
+			fw, fh := w.GetFramebufferSize()
+			Client.playerList.spectate(xpos*(float64(fw)/float64(width)), ypos*(float64(fh)/float64(height)), fw, fh)
+		}
+		return
+	}
 	if !Client.chat.enteringText && lockMouse && action != glfw.Repeat {
 		Client.MouseAction(button, action == glfw.Press)
 	}
@@ -198,7 +207,10 @@ func onChar(w *glfw.Window, char rune) {
 	}
 }
 
-var lastJumpPress = time.Now()
+var (
+	lastJumpPress    = time.Now()
+	lastForwardPress = time.Now()
+)
 
 func onKey(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
 	// Debug override
@@ -217,15 +229,25 @@ func onKey(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods
 	}
 
 	// For creative flying
-	if Client.GameMode.Fly() && keyStateMap[key] == KeyJump && action == glfw.Press {
+	if Client.AllowFlying && !Client.GameMode.NoClip() && !Client.riding && keyStateMap[key] == KeyJump && action == glfw.Press {
 		now := time.Now()
 		if now.Sub(lastJumpPress) < 500*time.Millisecond {
-			Client.isFlying = !Client.isFlying
+			Client.setFlying(!Client.isFlying)
 		}
 		lastJumpPress = now
 	}
 
-	if k, ok := keyStateMap[key]; action != glfw.Repeat && ok {
+	// Double tapping forward starts sprinting
+	if k, ok := keyStateMap[key]; ok && k == KeyForward && action == glfw.Press && Client.cameraMode != cameraEntity {
+		now := time.Now()
+		if now.Sub(lastForwardPress) < 350*time.Millisecond {
+			Client.setSprinting(true)
+		}
+		lastForwardPress = now
+	}
+
+	// Movement is blocked whilst viewing from another entity
+	if k, ok := keyStateMap[key]; action != glfw.Repeat && ok && Client.cameraMode != cameraEntity {
 		Client.KeyState[k] = action == glfw.Press
 	}
 	if key >= glfw.Key0 && key <= glfw.Key9 && action == glfw.Press {
@@ -259,8 +281,18 @@ func onKey(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods
 	case glfw.KeyTab:
 		if action == glfw.Press {
 			Client.playerList.set(true)
+			// Spectators can pick a player to teleport to
+			// from the list
+			if Client.GameMode == gmSpecator {
+				lockMouse = false
+				w.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+			}
 		} else if action == glfw.Release {
 			Client.playerList.set(false)
+			if Client.GameMode == gmSpecator {
+				lockMouse = true
+				w.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+			}
 		}
 	case glfw.KeyE:
 		if action == glfw.Release {
@@ -103,6 +103,16 @@ func writeType(w io.Writer, v interface{}) error {
 	case *Compound:
 		return v.serialize(w)
 	case []int:
+		if err := binary.Write(w, binary.BigEndian, int32(len(v))); err != nil {
+			return err
+		}
+		// binary can't write ints directly
+		vals := make([]int32, len(v))
+		for i, val := range v {
+			vals[i] = int32(val)
+		}
+		return binary.Write(w, binary.BigEndian, vals)
+	case []int32:
 		if err := binary.Write(w, binary.BigEndian, int32(len(v))); err != nil {
 			return err
 		}
@@ -133,7 +143,7 @@ func getTypeID(i interface{}) TypeID {
 		return TagList
 	case *Compound:
 		return TagCompound
-	case []int:
+	case []int, []int32:
 		return TagIntArray
 	}
 	panic(fmt.Sprintf("invalid type %T", i))
@@ -24,6 +24,7 @@ func newPlayer() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		playerComponent
 		playerModelComponent
@@ -44,6 +45,7 @@ func newCreeper() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -63,6 +65,7 @@ func newSkeleton() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -82,6 +85,7 @@ func newSpider() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -101,6 +105,7 @@ func newZombie() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -120,6 +125,7 @@ func newSlime() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -139,6 +145,7 @@ func newGhast() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -158,6 +165,7 @@ func newZombiePigman() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -177,6 +185,7 @@ func newEnderman() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -196,6 +205,7 @@ func newCaveSpider() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -215,6 +225,7 @@ func newSilverfish() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -234,6 +245,7 @@ func newBlaze() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -253,6 +265,7 @@ func newMagmaCube() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -272,6 +285,7 @@ func newEnderDragon() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -291,6 +305,7 @@ func newWither() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -310,6 +325,7 @@ func newBat() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -329,6 +345,7 @@ func newWitch() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -348,6 +365,7 @@ func newEndermite() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -367,6 +385,7 @@ func newGuardian() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -386,6 +405,7 @@ func newPig() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -405,6 +425,7 @@ func newSheep() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -424,6 +445,7 @@ func newCow() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -443,6 +465,7 @@ func newChicken() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -462,6 +485,7 @@ func newSquid() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -481,6 +505,7 @@ func newWolf() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -500,6 +525,7 @@ func newMooshroom() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -519,6 +545,7 @@ func newSnowman() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -538,6 +565,7 @@ func newOcelot() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -557,6 +585,7 @@ func newIronGolem() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -576,6 +605,7 @@ func newHorse() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -595,6 +625,7 @@ func newRabbit() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -614,6 +645,7 @@ func newVillager() Entity {
 		targetRotationComponent
 		targetPositionComponent
 		sizeComponent
+		hurtComponent
 
 		debugComponent
 	}
@@ -103,7 +103,9 @@ func staticModelFromItem(mdl *model, block Block, mode string) (out []*render.Mo
 	return
 }
 
-func genStaticModelFromItem(mdl *model, block Block, mode string) (out []*render.ModelVertex, mat mgl32.Mat4) {
+// genStaticModelFromItem builds a model from the item's texture.
+// Only the passed frame of textures with multiple frames is used.
+func genStaticModelFromItem(mdl *model, block Block, mode string, frame int) (out []*render.ModelVertex, mat mgl32.Mat4) {
 	mat = mgl32.Rotate3DZ(math.Pi).Mat4().
 		Mul4(mgl32.Rotate3DY(math.Pi / 2).Mat4()).
 		Mul4(mgl32.Rotate3DZ(-math.Pi / 2).Mat4())
@@ -151,11 +153,22 @@ func genStaticModelFromItem(mdl *model, block Block, mode string) (out []*render
 	}
 
 	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	// Frames are stacked vertically
+	oy := 0
+	if h > w {
+		if (frame+1)*w <= h {
+			oy = frame * w
+		}
+		h = w
+	}
 	sx := 1 / float32(w)
 	sy := 1 / float32(h)
 
 	isSolid := func(x, y int) bool {
-		col := img.At(x, y)
+		if y < 0 || y >= h {
+			return false
+		}
+		col := img.At(x, oy+y)
 		_, _, _, aa := col.RGBA()
 		if aa == 0 {
 			return false
@@ -165,7 +178,7 @@ func genStaticModelFromItem(mdl *model, block Block, mode string) (out []*render
 
 	for x := 0; x < w; x++ {
 		for y := 0; y < h; y++ {
-			col := img.At(x, y)
+			col := img.At(x, oy+y)
 			rr, gg, bb, aa := col.RGBA()
 			if aa == 0 {
 				continue
@@ -89,12 +89,18 @@ type playerModelComponent struct {
 	idleTime   float64
 	manualMove bool
 	walking    bool
+	sneaking   bool
 
 	armTime  float64
 	capeTime float64
 
 	heldModel *render.Model
 	heldMat   mgl32.Mat4
+	// Set when the held item displays a frame of one of
+	// the itemTextures so it can be rebuilt when it changes
+	heldItem    *ItemStack
+	heldTexture string
+	heldFrame   int
 }
 
 func (p *playerModelComponent) Model() *render.Model { return p.model }
@@ -103,10 +109,16 @@ func (p *playerModelComponent) SwingArm() {
 	p.armTime = 15
 }
 
+func (p *playerModelComponent) SetSneaking(sneaking bool) {
+	p.sneaking = sneaking
+}
+
 func (p *playerModelComponent) SetCurrentItem(item *ItemStack) {
 	if p.heldModel != nil {
 		p.heldModel.Free()
+		p.heldModel = nil
 	}
+	p.heldItem, p.heldTexture = nil, ""
 	if item == nil {
 		return
 	}
@@ -123,7 +135,12 @@ func (p *playerModelComponent) SetCurrentItem(item *ItemStack) {
 
 	var out []*render.ModelVertex
 	if mdl.builtIn == builtInGenerated {
-		out, p.heldMat = genStaticModelFromItem(mdl, blk, mode)
+		name := mdl.textureName("#layer0")
+		frame, _ := itemTextureFrame(name)
+		if _, ok := itemTextures[name]; ok {
+			p.heldItem, p.heldTexture, p.heldFrame = item, name, frame
+		}
+		out, p.heldMat = genStaticModelFromItem(mdl, blk, mode, frame)
 	} else if mdl.builtIn == builtInFalse {
 		out, p.heldMat = staticModelFromItem(mdl, blk, mode)
 	}
@@ -136,6 +153,7 @@ func (p *playerModelComponent) SetCurrentItem(item *ItemStack) {
 
 type PlayerModelComponent interface {
 	SwingArm()
+	SetSneaking(sneaking bool)
 	SetCurrentItem(item *ItemStack)
 }
 
@@ -335,6 +353,12 @@ func esPlayerModelTick(p *playerModelComponent,
 	x, y, z := pos.Position()
 	model := p.model
 
+	if p.heldTexture != "" {
+		if frame, _ := itemTextureFrame(p.heldTexture); frame != p.heldFrame {
+			p.SetCurrentItem(p.heldItem)
+		}
+	}
+
 	model.X, model.Y, model.Z = -float32(x), -float32(y), float32(z)
 	if p.heldModel != nil {
 		p.heldModel.X, p.heldModel.Y, p.heldModel.Z = -float32(x), -float32(y), float32(z)
@@ -370,9 +394,20 @@ func esPlayerModelTick(p *playerModelComponent,
 			Mul4(mgl32.Rotate3DY(float32(val)).Mat4())
 	}
 
-	model.Matrix[playerModelHead] = offMat.Mul4(mgl32.Translate3D(0, -12/16.0-12/16.0, 0)).
+	// Crouching lowers the upper body and leans it forward
+	// using the same offsets as vanilla
+	var crouch, headDrop, lean, armLean, legOffset float32
+	if p.sneaking {
+		crouch, headDrop = 0.2, 1/16.0
+		lean, armLean = 0.5, 0.4
+		legOffset = 4 / 16.0
+	}
+
+	model.Matrix[playerModelHead] = offMat.Mul4(mgl32.Translate3D(0, -12/16.0-12/16.0+crouch+headDrop, 0)).
 		Mul4(mgl32.Rotate3DX(float32(r.Pitch())).Mat4())
-	model.Matrix[playerModelBody] = offMat.Mul4(mgl32.Translate3D(0, -12/16.0-6/16.0, 0))
+	model.Matrix[playerModelBody] = offMat.Mul4(mgl32.Translate3D(0, -12/16.0-12/16.0+crouch, 0)).
+		Mul4(mgl32.Rotate3DX(lean).Mat4()).
+		Mul4(mgl32.Translate3D(0, 6/16.0, 0))
 
 	time := p.time
 	dir := p.dir
@@ -382,9 +417,9 @@ func esPlayerModelTick(p *playerModelComponent,
 	}
 	ang := ((time / 15) - 1) * (math.Pi / 4)
 
-	model.Matrix[playerModelLegRight] = offMat.Mul4(mgl32.Translate3D(2/16.0, -12/16.0, 0)).
+	model.Matrix[playerModelLegRight] = offMat.Mul4(mgl32.Translate3D(2/16.0, -12/16.0, legOffset)).
 		Mul4(mgl32.Rotate3DX(float32(ang)).Mat4())
-	model.Matrix[playerModelLegLeft] = offMat.Mul4(mgl32.Translate3D(-2/16.0, -12/16.0, 0)).
+	model.Matrix[playerModelLegLeft] = offMat.Mul4(mgl32.Translate3D(-2/16.0, -12/16.0, legOffset)).
 		Mul4(mgl32.Rotate3DX(-float32(ang)).Mat4())
 
 	iTime := p.idleTime
@@ -400,14 +435,16 @@ func esPlayerModelTick(p *playerModelComponent,
 		p.armTime -= Client.delta
 	}
 
-	model.Matrix[playerModelArmRight] = offMat.Mul4(mgl32.Translate3D(6/16.0, -12/16.0-12/16.0, 0))
+	model.Matrix[playerModelArmRight] = offMat.Mul4(mgl32.Translate3D(6/16.0, -12/16.0-12/16.0+crouch, 0)).
+		Mul4(mgl32.Rotate3DX(armLean).Mat4())
 	model.Matrix[playerModelArmRight] = model.Matrix[playerModelArmRight].
 		Mul4(mgl32.Rotate3DX(-float32(ang * 0.75)).Mat4()).
 		Mul4(mgl32.Rotate3DZ(float32(math.Cos(iTime)*0.06) - 0.06).Mat4()).
 		Mul4(mgl32.Rotate3DX(float32(math.Sin(iTime)*0.06) - float32((7.5-math.Abs(p.armTime-7.5))/7.5)).Mat4())
 
 	if p.heldModel != nil {
-		p.heldModel.Matrix[0] = offMat.Mul4(mgl32.Translate3D(6/16.0, -12/16.0-12/16.0, 0.0)).
+		p.heldModel.Matrix[0] = offMat.Mul4(mgl32.Translate3D(6/16.0, -12/16.0-12/16.0+crouch, 0.0)).
+			Mul4(mgl32.Rotate3DX(armLean).Mat4()).
 			Mul4(mgl32.Rotate3DX(-float32(ang * 0.75)).Mat4()).
 			Mul4(mgl32.Rotate3DZ(float32(math.Cos(iTime)*0.06) - 0.06).Mat4()).
 			Mul4(mgl32.Rotate3DX(float32(math.Sin(iTime)*0.06) - float32((7.5-math.Abs(p.armTime-7.5))/7.5)).Mat4()).
@@ -415,7 +452,8 @@ func esPlayerModelTick(p *playerModelComponent,
 			Mul4(p.heldMat)
 	}
 
-	model.Matrix[playerModelArmLeft] = offMat.Mul4(mgl32.Translate3D(-6/16.0, -12/16.0-12/16.0, 0)).
+	model.Matrix[playerModelArmLeft] = offMat.Mul4(mgl32.Translate3D(-6/16.0, -12/16.0-12/16.0+crouch, 0)).
+		Mul4(mgl32.Rotate3DX(armLean).Mat4()).
 		Mul4(mgl32.Rotate3DX(float32(ang * 0.75)).Mat4()).
 		Mul4(mgl32.Rotate3DZ(-float32(math.Cos(iTime)*0.06) + 0.06).Mat4()).
 		Mul4(mgl32.Rotate3DX(-float32(math.Sin(iTime) * 0.06)).Mat4())
@@ -171,3 +171,21 @@ func (d debugComponent) DebugColor() (r, g, b byte) {
 type DebugComponent interface {
 	DebugColor() (r, g, b byte)
 }
+
+// Hurt
+
+// How long (in 1/60ths of a second) an entity is tinted
+// red for after being hurt. Vanilla uses 10 ticks.
+const entityHurtTime = 30
+
+type hurtComponent struct {
+	hurtTimer float64
+}
+
+func (h *hurtComponent) Hurt()        { h.hurtTimer = entityHurtTime }
+func (h *hurtComponent) IsHurt() bool { return h.hurtTimer > 0 }
+
+type HurtComponent interface {
+	Hurt()
+	IsHurt() bool
+}
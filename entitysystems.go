@@ -27,6 +27,8 @@ func init() {
 	addSystem(entitysys.Tick, esRotateToTarget)
 	addSystem(entitysys.Tick, esDrawOutline)
 	addSystem(entitysys.Tick, esLightModel)
+	addSystem(entitysys.Tick, esHurtTick)
+	addSystem(entitysys.Tick, esHurtModel)
 	addSystem(entitysys.Tick, esMoveChunk)
 }
 
@@ -35,6 +37,9 @@ func esDrawOutline(p PositionComponent, s SizeComponent, d DebugComponent) {
 	bounds := s.Bounds().Shift(float32(x), float32(y), float32(z))
 
 	r, g, b := d.DebugColor()
+	if h, ok := d.(HurtComponent); ok && h.IsHurt() {
+		r, g, b = 255, 0, 0
+	}
 	render.DrawBox(
 		float64(bounds.Min.X()),
 		float64(bounds.Min.Y()),
@@ -46,6 +51,29 @@ func esDrawOutline(p PositionComponent, s SizeComponent, d DebugComponent) {
 	)
 }
 
+func esHurtTick(h *hurtComponent) {
+	if h.hurtTimer > 0 {
+		h.hurtTimer -= Client.delta
+	}
+}
+
+// esHurtModel tints the model red whilst the entity is hurt
+func esHurtModel(h *hurtComponent, m interface {
+	Model() *render.Model
+}) {
+	model := m.Model()
+	if model == nil {
+		return
+	}
+	col := [4]float32{1.0, 1.0, 1.0, 1.0}
+	if h.IsHurt() {
+		col = [4]float32{1.0, 0.6, 0.6, 1.0}
+	}
+	for i := range model.Colors {
+		model.Colors[i] = col
+	}
+}
+
 // updates the Colors of the model to fake lighting
 func esLightModel(p PositionComponent, s SizeComponent, m interface {
 	Model() *render.Model
@@ -91,11 +119,11 @@ func esLightModel(p PositionComponent, s SizeComponent, m interface {
 func esMoveChunk(e Entity, p *positionComponent) {
 	cx, cz := int(p.X)>>4, int(p.Z)>>4
 	if cx != p.CX || cz != p.CZ {
-		oc := chunkMap[chunkPosition{p.CX, p.CZ}]
+		oc := chunkMap.chunks[chunkPosition{p.CX, p.CZ}]
 		if oc != nil {
 			oc.removeEntity(e)
 		}
-		c := chunkMap[chunkPosition{cx, cz}]
+		c := chunkMap.chunks[chunkPosition{cx, cz}]
 		if c != nil {
 			c.addEntity(e)
 			p.CX, p.CZ = cx, cz
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/world"
 )
 
 var fakeGenDistance = 7
@@ -49,6 +50,7 @@ func fakeGen() {
 		mid = Blocks.EndStone
 		bot = Blocks.EndStone
 	}
+	chunkMap.data.HasSky = ra == 0
 	go func() {
 		randGrid := make([]int, (fakeGenDistance*2+1)*(fakeGenDistance*2+1))
 		for i := range randGrid {
@@ -74,22 +76,16 @@ func fakeGen() {
 
 		for cx := -fakeGenDistance; cx <= fakeGenDistance; cx++ {
 			for cz := -fakeGenDistance; cz <= fakeGenDistance; cz++ {
-				c := &chunk{
-					chunkPosition: chunkPosition{
-						X: cx, Z: cz,
-					},
-				}
+				c := world.NewChunk(cx, cz)
 
 				for i := 0; i < 4; i++ {
-					cs := newChunkSection(c, i)
-					c.Sections[i] = cs
+					cs := c.Section(i)
 					for y := 0; y < 16; y++ {
 						for z := 0; z < 16; z++ {
 							for x := 0; x < 16; x++ {
 								height := smooth(cx, cz, x, y, z)
 								ry := y + i<<4
 								var block Block
-								cs.setSkyLight(0, x, y, z)
 								switch {
 								case ry <= height-5:
 									block = bot.Base
@@ -97,30 +93,19 @@ func fakeGen() {
 									block = mid.Base
 								case ry == height:
 									block = top.Base
+								case ry >= 60:
+									block = Blocks.Air.Base
 								default:
-									level := 0xF
-									if ry >= 60 {
-										block = Blocks.Air.Base
-									} else {
-										block = liquid.Base
-										if liquid == Blocks.Water {
-											level = 13 - (60-ry)*2
-										}
-									}
-									if level < 0 {
-										level = 0
-									}
-									sky := (16*16*16*2 + 16*16*8) * 4
-									sky += 16 * 16 * 8 * i
-									cs.setSkyLight(byte(level), x, y, z)
+									block = liquid.Base
 								}
-								cs.setBlock(block, x, y, z)
+								cs.SetBlock(block.SID(), x, y, z)
 							}
 						}
 					}
 				}
-				c.calcHeightmap()
-				syncChan <- c.postLoad
+				c.CalcHeightmap()
+				world.LightChunk(c, blockRegistry{}, ra == 0)
+				syncChan <- func() { chunkMap.addChunk(c) }
 			}
 		}
 	}()
@@ -111,3 +111,21 @@ func ConvertLegacy(c AnyComponent) {
 		panic("unhandled component")
 	}
 }
+
+// StripLegacy removes any legacy formatting codes (e.g. §a)
+// from the passed string.
+func StripLegacy(str string) string {
+	if !strings.ContainsRune(str, legacyChar) {
+		return str
+	}
+	text := []rune(str)
+	out := make([]rune, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		if text[i] == legacyChar {
+			i++
+			continue
+		}
+		out = append(out, text[i])
+	}
+	return string(out)
+}
@@ -67,13 +67,21 @@ func (h handler) Handle(packet interface{}) {
 func (handler) ServerMessage(msg *protocol.ServerMessage) {
 	console.Text("MSG(%d): %s", msg.Type, msg.Message.Value)
 	Client.chat.Add(msg.Message)
+	Client.achievements.checkMessage(msg.Message)
+}
+
+func (handler) TabCompleteReply(p *protocol.TabCompleteReply) {
+	Client.chat.handleCompletions(p.Matches)
 }
 
 func (handler) JoinGame(j *protocol.JoinGame) {
 	clearChunks()
+	Client.setVehicle(-1)
+	Client.placePredictions.clear()
 	sendPluginMessage(&pmMinecraftBrand{
 		Brand: "Steven",
 	})
+	Client.entityID = int(j.EntityID)
 	Client.GameMode = gameMode(j.Gamemode & 0x7)
 	Client.HardCore = j.Gamemode&0x8 != 0
 	Client.updateWorldType(worldType(j.Dimension))
@@ -81,6 +89,8 @@ func (handler) JoinGame(j *protocol.JoinGame) {
 
 func (handler) Respawn(r *protocol.Respawn) {
 	clearChunks()
+	Client.setVehicle(-1)
+	Client.placePredictions.clear()
 	Client.GameMode = gameMode(r.Gamemode & 0x7)
 	Client.HardCore = r.Gamemode&0x8 != 0
 	Client.updateWorldType(worldType(r.Dimension))
@@ -119,23 +129,145 @@ func (handler) ChangeGameState(c *protocol.ChangeGameState) {
 	}
 }
 
+func (handler) PlayerAbilities(p *protocol.PlayerAbilities) {
+	flags := abilityFlag(p.Flags)
+	Client.Invulnerable = flags&abilityInvulnerable != 0
+	Client.isFlying = flags&abilityFlying != 0
+	Client.AllowFlying = flags&abilityAllowFlying != 0
+	Client.Creative = flags&abilityCreative != 0
+	Client.FlyingSpeed = float64(p.FlyingSpeed)
+	Client.WalkingSpeed = float64(p.WalkingSpeed)
+}
+
+func (handler) WorldBorder(p *protocol.WorldBorder) {
+	wb := &Client.worldBorder
+	switch p.Action {
+	case 0: // Set size
+		wb.setSize(p.NewRadius)
+	case 1: // Lerp size
+		wb.lerpSize(p.OldRadius, p.NewRadius, int64(p.Speed))
+	case 2: // Set center
+		wb.centerX, wb.centerZ = p.X, p.Z
+	case 3: // Initialize
+		wb.centerX, wb.centerZ = p.X, p.Z
+		wb.lerpSize(p.OldRadius, p.NewRadius, int64(p.Speed))
+		wb.portalBoundary = int(p.PortalBoundary)
+		wb.warningTime = int(p.WarningTime)
+		wb.warningBlocks = int(p.WarningBlocks)
+	case 4: // Set warning time
+		wb.warningTime = int(p.WarningTime)
+	case 5: // Set warning blocks
+		wb.warningBlocks = int(p.WarningBlocks)
+	}
+}
+
+func (handler) Camera(p *protocol.Camera) {
+	Client.setCameraEntity(int(p.TargetID))
+}
+
+func (handler) ResourcePackSend(p *protocol.ResourcePackSend) {
+	setScreen(newServerPackPrompt(p.URL, p.Hash))
+}
+
+func (handler) CombatEvent(p *protocol.CombatEvent) {
+	switch p.Event {
+	case 0: // Enter combat
+		Client.inCombat = true
+	case 1: // End combat
+		Client.inCombat = false
+	case 2: // Entity dead
+		if int(p.PlayerID) != Client.entityID {
+			return
+		}
+		Client.inCombat = false
+		Client.deathMessage = p.Message
+		if rs, ok := currentScreen.(*respawnScreen); ok {
+			rs.setMessage(p.Message)
+			return
+		}
+		setScreen(newRespawnScreen())
+	}
+}
+
+func (handler) SetExperience(p *protocol.SetExperience) {
+	// Vanilla's score goes up with the experience collected
+	Client.Score = int(p.TotalExperience)
+}
+
+func (handler) SetCooldown(p *protocol.SetCooldown) {
+	Client.itemCooldowns.set(int(p.ItemID), int(p.Ticks))
+}
+
+func (handler) PlayerListHeaderFooter(p *protocol.PlayerListHeaderFooter) {
+	Client.playerList.setHeaderFooter(p.Header, p.Footer)
+}
+
+func (handler) Statistics(p *protocol.Statistics) {
+	for _, s := range p.Statistics {
+		Client.statistics[s.Name] = int(s.Value)
+	}
+	if sm, ok := currentScreen.(*statisticsMenu); ok {
+		sm.update()
+	}
+}
+
+func (handler) ServerDifficulty(p *protocol.ServerDifficulty) {
+	Client.Difficulty = difficulty(p.Difficulty)
+}
+
+func (handler) SpawnPosition(p *protocol.SpawnPosition) {
+	Client.SpawnPosition = Position{
+		X: p.Location.X(),
+		Y: p.Location.Y(),
+		Z: p.Location.Z(),
+	}
+}
+
+func (handler) EntityAttach(p *protocol.EntityAttach) {
+	// Leashes don't change how the player moves
+	if p.Leash || int(p.EntityID) != Client.entityID {
+		return
+	}
+	Client.setVehicle(int(p.Vehicle))
+}
+
 func (handler) ChangeHotbarSlot(s *protocol.SetCurrentHotbarSlot) {
 	Client.currentHotbarSlot = int(s.Slot)
 }
 
 func (handler) Teleport(t *protocol.TeleportPlayer) {
-	Client.X = calculateTeleport(teleportRelX, t.Flags, Client.X, t.X)
-	Client.Y = calculateTeleport(teleportRelY, t.Flags, Client.Y, t.Y)
-	Client.Z = calculateTeleport(teleportRelZ, t.Flags, Client.Z, t.Z)
-	Client.Yaw = calculateTeleport(teleportRelYaw, t.Flags, Client.Yaw, float64(-t.Yaw)*(math.Pi/180))
-	Client.Pitch = calculateTeleport(teleportRelPitch, t.Flags, Client.Pitch, -float64(t.Pitch)*(math.Pi/180)+math.Pi)
-	Client.checkGround()
+	ph := &Client.physics
+	x := calculateTeleport(teleportRelX, t.Flags, ph.x, t.X)
+	y := calculateTeleport(teleportRelY, t.Flags, ph.y, t.Y)
+	z := calculateTeleport(teleportRelZ, t.Flags, ph.z, t.Z)
+	// Like vanilla the motion is only kept for relative
+	// movements
+	if t.Flags&byte(teleportRelX) == 0 {
+		ph.motionX = 0
+	}
+	if t.Flags&byte(teleportRelY) == 0 {
+		ph.motionY = 0
+	}
+	if t.Flags&byte(teleportRelZ) == 0 {
+		ph.motionZ = 0
+	}
+	ph.teleport(x, y, z)
+	Client.X, Client.Y, Client.Z = x, y, z
+	Client.OnGround = ph.onGround
+	// The rotation is worked out in the protocol's degrees so
+	// that relative changes are applied the same way as vanilla
+	yaw := calculateTeleport(teleportRelYaw, t.Flags, -Client.Yaw*(180/math.Pi), float64(t.Yaw))
+	pitch := calculateTeleport(teleportRelPitch, t.Flags, (-Client.Pitch-math.Pi)*(180/math.Pi), float64(t.Pitch))
+	Client.Yaw = -yaw * (math.Pi / 180)
+	Client.Pitch = -pitch*(math.Pi/180) + math.Pi
+	// The server expects the final position and rotation back,
+	// not the relative values it sent
 	Client.network.Write(&protocol.PlayerPositionLook{
-		X:        t.X,
-		Y:        t.Y,
-		Z:        t.Z,
-		Yaw:      t.Yaw,
-		Pitch:    t.Pitch,
+		X:        x,
+		Y:        y,
+		Z:        z,
+		Yaw:      float32(yaw),
+		Pitch:    float32(pitch),
 		OnGround: Client.OnGround,
 	})
 	Client.copyToCamera()
@@ -148,6 +280,7 @@ var loadingChunks = map[chunkPosition][]func(){}
 func (handler) ChunkData(c *protocol.ChunkData) {
 	pos := chunkPosition{int(c.ChunkX), int(c.ChunkZ)}
 	loadingChunks[pos] = nil
+	Client.placePredictions.resolveChunk(pos)
 
 	data := bytes.NewReader(c.Data)
 	if c.New {
@@ -158,12 +291,7 @@ func (handler) ChunkData(c *protocol.ChunkData) {
 }
 
 func (handler) ChunkUnload(p *protocol.ChunkUnload) {
-	pos := chunkPosition{int(p.X), int(p.Z)}
-	c, ok := chunkMap[pos]
-	if ok {
-		c.free()
-		delete(chunkMap, pos)
-	}
+	chunkMap.removeChunk(int(p.X), int(p.Z))
 }
 
 func protocolPosToChunkPos(p protocol.Position) chunkPosition {
@@ -177,6 +305,7 @@ func (handler) SetBlock(b *protocol.BlockChange) {
 		return
 	}
 
+	Client.placePredictions.resolve(Position{X: b.Location.X(), Y: b.Location.Y(), Z: b.Location.Z()})
 	block := GetBlockByCombinedID(uint16(b.BlockID))
 	chunkMap.SetBlock(block, b.Location.X(), b.Location.Y(), b.Location.Z())
 	chunkMap.UpdateBlock(b.Location.X(), b.Location.Y(), b.Location.Z())
@@ -189,16 +318,21 @@ func (handler) SetBlockBatch(b *protocol.MultiBlockChange) {
 		return
 	}
 
-	chunk := chunkMap[cp]
+	chunk := chunkMap.chunks[cp]
 	if chunk == nil {
 		return
 	}
-	for _, r := range b.Records {
-		block := GetBlockByCombinedID(uint16(r.BlockID))
-		x, y, z := int(r.XZ>>4), int(r.Y), int(r.XZ&0xF)
-		chunk.setBlock(block, x, y, z)
-		chunkMap.UpdateBlock((chunk.X<<4)+x, y, (chunk.Z<<4)+z)
-	}
+	// Relight once after all of the changes instead of after
+	// each one
+	chunkMap.data.Batch(func() {
+		for _, r := range b.Records {
+			block := GetBlockByCombinedID(uint16(r.BlockID))
+			x, y, z := (chunk.X<<4)+int(r.XZ>>4), int(r.Y), (chunk.Z<<4)+int(r.XZ&0xF)
+			Client.placePredictions.resolve(Position{X: x, Y: y, Z: z})
+			chunkMap.SetBlock(block, x, y, z)
+			chunkMap.UpdateBlock(x, y, z)
+		}
+	})
 }
 
 func (handler) BlockEntity(p *protocol.UpdateBlockEntity) {
@@ -207,6 +341,9 @@ func (handler) BlockEntity(p *protocol.UpdateBlockEntity) {
 		loadingChunks[cp] = append(f, func() { defaultHandler.BlockEntity(p) })
 		return
 	}
+	if c := chunkMap.chunks[cp]; c != nil {
+		c.setBlockEntityTag(Position{X: p.Location.X(), Y: p.Location.Y(), Z: p.Location.Z()}, p.NBT)
+	}
 
 	be := chunkMap.BlockEntity(p.Location.X(), p.Location.Y(), p.Location.Z())
 	if be == nil {
@@ -219,6 +356,21 @@ func (handler) BlockEntity(p *protocol.UpdateBlockEntity) {
 	nbe.Deserilize(p.NBT)
 }
 
+func (handler) BlockAction(p *protocol.BlockAction) {
+	cp := protocolPosToChunkPos(p.Location)
+	if f, ok := loadingChunks[cp]; ok {
+		loadingChunks[cp] = append(f, func() { defaultHandler.BlockAction(p) })
+		return
+	}
+
+	// The meaning of the action depends on the block so the
+	// block's entity handles it
+	be := chunkMap.BlockEntity(p.Location.X(), p.Location.Y(), p.Location.Z())
+	if ba, ok := be.(BlockActionComponent); ok {
+		ba.BlockAction(p.Byte1, p.Byte2)
+	}
+}
+
 func (handler) SignUpdate(p *protocol.UpdateSign) {
 	cp := protocolPosToChunkPos(p.Location)
 	if f, ok := loadingChunks[cp]; ok {
@@ -234,12 +386,25 @@ func (handler) SignUpdate(p *protocol.UpdateSign) {
 	if !ok {
 		return
 	}
-	s.Update([4]format.AnyComponent{
+	lines := [4]format.AnyComponent{
 		p.Line1,
 		p.Line2,
 		p.Line3,
 		p.Line4,
-	})
+	}
+	s.Update(lines)
+	if c := chunkMap.chunks[cp]; c != nil {
+		c.setBlockEntityTag(Position{X: p.Location.X(), Y: p.Location.Y(), Z: p.Location.Z()}, signTag(lines))
+	}
+}
+
+func (handler) SignEditorOpen(p *protocol.SignEditorOpen) {
+	cp := protocolPosToChunkPos(p.Location)
+	if f, ok := loadingChunks[cp]; ok {
+		loadingChunks[cp] = append(f, func() { defaultHandler.SignEditorOpen(p) })
+		return
+	}
+	setScreen(newSignEditor(Position{p.Location.X(), p.Location.Y(), p.Location.Z()}))
 }
 
 func (handler) BlockBreakAnimation(p *protocol.BlockBreakAnimation) {
@@ -420,6 +585,9 @@ func relMove(e Entity, dx, dy, dz float64) {
 func (handler) DestroyEntities(e *protocol.EntityDestroy) {
 	for _, id := range e.EntityIDs {
 		Client.entities.remove(int(id))
+		if Client.riding && int(id) == Client.vehicleID {
+			Client.setVehicle(-1)
+		}
 	}
 }
 
@@ -433,6 +601,53 @@ func (handler) Animation(p *protocol.Animation) {
 		if p, ok := e.(PlayerModelComponent); ok {
 			p.SwingArm()
 		}
+	case 1: // Take damage
+		if h, ok := e.(HurtComponent); ok {
+			h.Hurt()
+		}
+	case 4: // Critical hit
+		Client.particles.spawnCrits(e)
+	}
+}
+
+func (handler) EntityStatus(p *protocol.EntityAction) {
+	e, ok := Client.entities.entities[int(p.EntityID)]
+	if !ok {
+		return
+	}
+	switch p.ActionID {
+	case 2: // Hurt
+		if h, ok := e.(HurtComponent); ok {
+			h.Hurt()
+		}
+	}
+}
+
+func (handler) EntityVelocity(p *protocol.EntityVelocity) {
+	// Only the player is simulated, knockback and the like
+	if int(p.EntityID) != Client.entityID {
+		return
+	}
+	Client.physics.motionX = float64(p.VelocityX) / 8000
+	Client.physics.motionY = float64(p.VelocityY) / 8000
+	Client.physics.motionZ = float64(p.VelocityZ) / 8000
+}
+
+func (handler) Explosion(p *protocol.Explosion) {
+	Client.physics.motionX += float64(p.VelocityX)
+	Client.physics.motionY += float64(p.VelocityY)
+	Client.physics.motionZ += float64(p.VelocityZ)
+}
+
+func (handler) EntityMetadata(p *protocol.EntityMetadata) {
+	e, ok := Client.entities.entities[int(p.EntityID)]
+	if !ok {
+		return
+	}
+	if flags, ok := p.Metadata[0].(int8); ok {
+		if pl, ok := e.(PlayerModelComponent); ok {
+			pl.SetSneaking(flags&0x02 != 0)
+		}
 	}
 }
 
@@ -304,11 +304,17 @@ func createItemIcon(item *ItemStack, scene *scene.Type, x, y float64) *ui.Contai
 				if _, ok := mdl.textureVars[v]; !ok {
 					break
 				}
-				tex = mdl.lookupTexture("#" + v)
-
-				img := ui.NewImage(tex, 0, 0, 32, 32, 0, 0, 1, 1, 255, 255, 255)
+				name := mdl.textureName("#" + v)
+				tex = render.GetTexture(name)
+				frame, frames := itemTextureFrame(name)
+
+				img := ui.NewImage(tex, 0, 0, 32, 32,
+					0, float64(frame)/float64(frames), 1, 1/float64(frames),
+					255, 255, 255,
+				)
 				img.AttachTo(container)
 				scene.AddDrawable(img.Attach(ui.Top, ui.Left))
+				addFrameIcon(name, img, scene)
 			}
 		}
 	} else if mdl.builtIn == builtInFalse {
@@ -335,6 +341,7 @@ func createItemIcon(item *ItemStack, scene *scene.Type, x, y float64) *ui.Contai
 		bar.AttachTo(barShadow)
 		scene.AddDrawable(bar.Attach(ui.Top, ui.Left))
 	}
+	Client.itemCooldowns.addOverlay(item, container, scene)
 	if item.Count > 1 {
 		txt := ui.NewText(fmt.Sprint(item.Count), -2, -2, 255, 255, 255).
 			Attach(ui.Bottom, ui.Right)
@@ -0,0 +1,119 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/ui"
+	"github.com/thinkofdeath/steven/ui/scene"
+)
+
+// itemCooldowns tracks the items that can't currently be
+// used as set by the server.
+type itemCooldowns struct {
+	items    map[int]*itemCooldown
+	overlays []*cooldownOverlay
+}
+
+type itemCooldown struct {
+	// Both in 1/60ths of a second
+	total, remaining float64
+}
+
+// cooldownOverlay is the overlay drawn on top of an item's
+// icon whilst it is cooling down.
+type cooldownOverlay struct {
+	id    int
+	img   *ui.Image
+	scene *scene.Type
+}
+
+// set starts a cooldown for the item with the passed id that
+// lasts for the passed number of ticks. A length of 0 clears
+// the cooldown.
+func (ic *itemCooldowns) set(id, ticks int) {
+	if ic.items == nil {
+		ic.items = map[int]*itemCooldown{}
+	}
+	if ticks <= 0 {
+		delete(ic.items, id)
+		return
+	}
+	// Server ticks are 20 a second
+	ic.items[id] = &itemCooldown{
+		total:     float64(ticks) * 3,
+		remaining: float64(ticks) * 3,
+	}
+}
+
+// Active returns whether the item with the passed id is
+// cooling down.
+func (ic *itemCooldowns) Active(id int) bool {
+	_, ok := ic.items[id]
+	return ok
+}
+
+// progress returns the fraction of the cooldown remaining for
+// the item with the passed id.
+func (ic *itemCooldowns) progress(id int) float64 {
+	c, ok := ic.items[id]
+	if !ok {
+		return 0
+	}
+	return c.remaining / c.total
+}
+
+// addOverlay adds a cooldown overlay to the passed item icon.
+func (ic *itemCooldowns) addOverlay(item *ItemStack, container *ui.Container, s *scene.Type) {
+	img := ui.NewImage(render.GetTexture("solid"), 0, 0, 32, 0, 0, 0, 1, 1, 255, 255, 255)
+	img.SetA(127)
+	img.SetLayer(2)
+	img.AttachTo(container)
+	s.AddDrawable(img.Attach(ui.Bottom, ui.Left))
+	o := &cooldownOverlay{
+		id:    int(item.rawID),
+		img:   img,
+		scene: s,
+	}
+	o.update(ic.progress(o.id))
+	ic.overlays = append(ic.overlays, o)
+}
+
+func (o *cooldownOverlay) update(progress float64) {
+	o.img.SetHeight(32 * progress)
+	o.img.SetDraw(progress > 0)
+}
+
+func (ic *itemCooldowns) tick(delta float64) {
+	for id, c := range ic.items {
+		c.remaining -= delta
+		if c.remaining <= 0 {
+			delete(ic.items, id)
+		}
+	}
+	overlays := ic.overlays[:0]
+	for _, o := range ic.overlays {
+		progress := ic.progress(o.id)
+		o.update(progress)
+		// Hidden scenes may be shown again (e.g. the hotbar)
+		// so overlays are only dropped once they have nothing
+		// left to display.
+		if progress == 0 && !o.scene.IsVisible() {
+			continue
+		}
+		overlays = append(overlays, o)
+	}
+	ic.overlays = overlays
+}
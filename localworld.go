@@ -0,0 +1,263 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sync"
+
+	"github.com/thinkofdeath/steven/console"
+	"github.com/thinkofdeath/steven/encoding/nbt"
+	"github.com/thinkofdeath/steven/format"
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/world"
+	"github.com/thinkofdeath/steven/world/anvil"
+)
+
+// maxLocalLoaders is the number of chunks that can be loading
+// from disk at once.
+const maxLocalLoaders = 4
+
+func init() {
+	console.Register("open_world %", openWorld)
+}
+
+// localWorld is set whilst viewing a world saved on disk
+// instead of a server.
+var localWorld *localSource
+
+// localSource streams chunks from a saved world into the
+// chunkMap around the camera.
+type localSource struct {
+	dir string
+
+	// loading contains the chunks currently being read and
+	// is only used from the main goroutine
+	loading map[chunkPosition]bool
+	loaders chan struct{}
+	closed  bool
+
+	// regions caches the region files the chunks around the
+	// camera are read from
+	regionLock sync.Mutex
+	regions    map[chunkPosition]*anvil.Region
+}
+
+// openWorld opens a world in the worlds directory or the path
+// passed for free-fly viewing. Worlds can't be opened whilst
+// connected to a server.
+func openWorld(name string) {
+	if connected && localWorld == nil {
+		console.Text("Disconnect from the server before opening a world")
+		return
+	}
+	dir := name
+	if filepath.Base(name) == name {
+		dir = filepath.Join(worldsDir, name)
+	}
+	level, err := anvil.ReadLevel(dir)
+	if err != nil {
+		console.Text("Failed to open the world: %s", err)
+		return
+	}
+
+	closeLocalWorld()
+	setScreen(nil)
+	connected = true
+	initClient()
+	disconnectReason.Value = nil
+	// There isn't a server to send packets to
+	Client.network.discard()
+	clearChunks()
+
+	Client.updateWorldType(wtOverworld)
+	Client.GameMode = gmSpecator
+	Client.Health = 20
+	Client.AllowFlying = true
+	Client.isFlying = true
+	Client.WorldTime = float64(level.DayTime % 24000)
+	Client.TargetWorldTime = Client.WorldTime
+	Client.SpawnPosition = Position{X: level.SpawnX, Y: level.SpawnY, Z: level.SpawnZ}
+	x, y, z := float64(level.SpawnX)+0.5, float64(level.SpawnY), float64(level.SpawnZ)+0.5
+	Client.physics.teleport(x, y, z)
+	Client.X, Client.Y, Client.Z = x, y, z
+
+	localWorld = &localSource{
+		dir:     dir,
+		loading: map[chunkPosition]bool{},
+		loaders: make(chan struct{}, maxLocalLoaders),
+		regions: map[chunkPosition]*anvil.Region{},
+	}
+	localWorld.tick()
+	ready = true
+	console.Text("Opened %s", level.Name)
+}
+
+// closeLocalWorld stops streaming the local world if one is
+// open.
+func closeLocalWorld() {
+	if localWorld == nil {
+		return
+	}
+	localWorld.closed = true
+	localWorld = nil
+}
+
+// tick loads the chunks near the camera and unloads the ones
+// that have moved out of range.
+func (l *localSource) tick() {
+	cx := int(math.Floor(Client.X)) >> 4
+	cz := int(math.Floor(Client.Z)) >> 4
+	viewDistance := render.ViewDistanceChunks()
+
+	for pos := range chunkMap.chunks {
+		if abs(pos.X-cx) > viewDistance+1 || abs(pos.Z-cz) > viewDistance+1 {
+			chunkMap.removeChunk(pos.X, pos.Z)
+		}
+	}
+	l.evictRegions(cx, cz, viewDistance+1)
+
+	// Load the closest chunks first
+	for r := 0; r <= viewDistance; r++ {
+		for x := cx - r; x <= cx+r; x++ {
+			for z := cz - r; z <= cz+r; z++ {
+				if abs(x-cx) != r && abs(z-cz) != r {
+					continue
+				}
+				pos := chunkPosition{x, z}
+				if chunkMap.chunks[pos] != nil || l.loading[pos] {
+					continue
+				}
+				select {
+				case l.loaders <- struct{}{}:
+				default:
+					return
+				}
+				l.loading[pos] = true
+				go l.load(pos)
+			}
+		}
+	}
+}
+
+// load reads the chunk from its region file and passes it to
+// the main goroutine to be added to the world.
+func (l *localSource) load(pos chunkPosition) {
+	defer func() { <-l.loaders }()
+	c, blockEntities, err := l.readChunk(pos)
+	syncChan <- func() {
+		delete(l.loading, pos)
+		if l.closed {
+			return
+		}
+		if err != nil {
+			console.Text("Failed to load chunk %d,%d: %s", pos.X, pos.Z, err)
+		}
+		if c == nil {
+			// Leave a space instead of retrying every tick
+			c = world.NewChunk(pos.X, pos.Z)
+		}
+		chunkMap.addChunk(c)
+		for _, tag := range blockEntities {
+			loadBlockEntityTag(tag)
+		}
+	}
+}
+
+func (l *localSource) readChunk(pos chunkPosition) (*world.Chunk, []*nbt.Compound, error) {
+	r, err := l.region(pos)
+	if err != nil {
+		return nil, nil, err
+	}
+	tag, err := r.Chunk(pos.X, pos.Z)
+	if tag == nil || err != nil {
+		return nil, nil, err
+	}
+	return anvil.DecodeChunk(tag, blockRegistry{}, true)
+}
+
+// region returns the region containing the chunk, reading it
+// from disk the first time it's needed.
+func (l *localSource) region(pos chunkPosition) (*anvil.Region, error) {
+	l.regionLock.Lock()
+	defer l.regionLock.Unlock()
+	rp := chunkPosition{pos.X >> 5, pos.Z >> 5}
+	if r, ok := l.regions[rp]; ok {
+		return r, nil
+	}
+	r, err := anvil.OpenRegion(anvil.RegionPath(l.dir, pos.X, pos.Z))
+	if err != nil {
+		return nil, err
+	}
+	l.regions[rp] = r
+	return r, nil
+}
+
+// evictRegions drops the cached regions that don't contain any
+// chunks within the distance of the passed chunk.
+func (l *localSource) evictRegions(cx, cz, distance int) {
+	l.regionLock.Lock()
+	defer l.regionLock.Unlock()
+	minX, maxX := (cx-distance)>>5, (cx+distance)>>5
+	minZ, maxZ := (cz-distance)>>5, (cz+distance)>>5
+	for rp := range l.regions {
+		if rp.X < minX || rp.X > maxX || rp.Z < minZ || rp.Z > maxZ {
+			delete(l.regions, rp)
+		}
+	}
+}
+
+// loadBlockEntityTag applies saved block entity nbt to the block
+// entity in the world.
+func loadBlockEntityTag(tag *nbt.Compound) {
+	x, xok := tag.Items["x"].(int32)
+	y, yok := tag.Items["y"].(int32)
+	z, zok := tag.Items["z"].(int32)
+	if !xok || !yok || !zok {
+		return
+	}
+	pos := Position{X: int(x), Y: int(y), Z: int(z)}
+	if c := chunkMap.chunks[chunkPosition{pos.X >> 4, pos.Z >> 4}]; c != nil {
+		c.setBlockEntityTag(pos, tag)
+	}
+	be := chunkMap.BlockEntity(pos.X, pos.Y, pos.Z)
+	if be == nil {
+		return
+	}
+	if s, ok := be.(SignComponent); ok {
+		var lines [4]format.AnyComponent
+		for i := range lines {
+			text, _ := tag.Items[fmt.Sprintf("Text%d", i+1)].(string)
+			if err := json.Unmarshal([]byte(text), &lines[i]); err != nil {
+				lines[i] = format.Wrap(&format.TextComponent{Text: text})
+			}
+		}
+		s.Update(lines)
+		return
+	}
+	if nbe, ok := be.(BlockNBTComponent); ok {
+		nbe.Deserilize(tag)
+	}
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
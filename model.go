@@ -474,10 +474,16 @@ func (bf *blockFace) init(data *jsBlockFace) {
 }
 
 func (bm *model) lookupTexture(name string) render.TextureInfo {
+	return render.GetTexture(bm.textureName(name))
+}
+
+// textureName returns the name of the texture, following
+// any texture variables.
+func (bm *model) textureName(name string) string {
 	if len(name) > 0 && name[0] == '#' {
-		return bm.lookupTexture(bm.textureVars[name[1:]])
+		return bm.textureName(bm.textureVars[name[1:]])
 	}
-	return render.GetTexture(name)
+	return name
 }
 
 func loadJSON(plugin, name string, target interface{}) error {
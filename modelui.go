@@ -16,10 +16,13 @@ package steven
 
 import (
 	"math"
+	"math/rand"
 
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/thinkofdeath/steven/render"
 	"github.com/thinkofdeath/steven/type/direction"
 	"github.com/thinkofdeath/steven/ui"
+	"github.com/thinkofdeath/steven/ui/scene"
 	"github.com/thinkofdeath/steven/world/biome"
 )
 
@@ -118,3 +121,109 @@ func modelToUI(mdl *model, block Block) *ui.Model {
 	}
 	return ui.NewModel(0, 0, verts, mat)
 }
+
+// itemTextures are item textures whose current frame is picked
+// by the client instead of being animated. Item models pick the
+// frame to display when they are built.
+var itemTextures = map[string]*itemTexture{
+	"items/compass": {next: compassFrame},
+}
+
+type itemTexture struct {
+	next  func(delta float64, frames int) int
+	frame int
+}
+
+// itemTextureFrame returns the frame of the named texture that
+// item models should display and the number of frames it has.
+func itemTextureFrame(name string) (frame, frames int) {
+	frames = render.FrameCount(name)
+	if t, ok := itemTextures[name]; ok {
+		frame = t.frame % frames
+	}
+	return frame, frames
+}
+
+// frameIcon is an item icon displaying one of the frames of
+// an item texture.
+type frameIcon struct {
+	texture string
+	img     *ui.Image
+	scene   *scene.Type
+}
+
+var frameIcons []*frameIcon
+
+// addFrameIcon keeps the icon's texture coordinates on the
+// current frame of the named texture.
+func addFrameIcon(texture string, img *ui.Image, s *scene.Type) {
+	if _, ok := itemTextures[texture]; !ok {
+		return
+	}
+	frameIcons = append(frameIcons, &frameIcon{
+		texture: texture,
+		img:     img,
+		scene:   s,
+	})
+}
+
+func tickItemTextures(delta float64) {
+	for name, t := range itemTextures {
+		t.frame = t.next(delta, render.FrameCount(name))
+	}
+	icons := frameIcons[:0]
+	for _, i := range frameIcons {
+		// The hotbar is hidden whilst the inventory is open
+		// but will be shown again
+		if !i.scene.IsVisible() && i.scene != Client.hotbarScene {
+			continue
+		}
+		frame, frames := itemTextureFrame(i.texture)
+		i.img.SetTextureY(float64(frame) / float64(frames))
+		icons = append(icons, i)
+	}
+	frameIcons = icons
+}
+
+var compass struct {
+	angle, angleDelta float64
+}
+
+// compassFrame returns the frame of the compass texture that
+// points towards the world's spawn. The needle swings towards
+// the target in the same way as vanilla's.
+func compassFrame(delta float64, frames int) int {
+	var target float64
+	if Client.WorldType == wtOverworld {
+		dx := float64(Client.SpawnPosition.X) - Client.X
+		dz := float64(Client.SpawnPosition.Z) - Client.Z
+		// Convert into vanilla's rotation
+		yaw := math.Mod(-Client.Yaw*(180/math.Pi), 360)
+		target = -((yaw-90)*(math.Pi/180) - math.Atan2(dz, dx))
+	} else {
+		// The needle spins randomly outside of the overworld
+		target = rand.Float64() * math.Pi * 2
+	}
+
+	diff := target - compass.angle
+	for diff < -math.Pi {
+		diff += math.Pi * 2
+	}
+	for diff >= math.Pi {
+		diff -= math.Pi * 2
+	}
+	diff = math.Max(-1, math.Min(1, diff))
+
+	// Vanilla updates at 20 ticks a second
+	ticks := delta / 3
+	compass.angleDelta += diff * 0.1 * ticks
+	compass.angleDelta *= math.Pow(0.8, ticks)
+	compass.angle += compass.angleDelta * ticks
+
+	frame := int((compass.angle/(math.Pi*2) + 1) * float64(frames))
+	frame %= frames
+	if frame < 0 {
+		frame += frames
+	}
+	return frame
+}
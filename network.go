@@ -163,6 +163,15 @@ func (n *networkManager) Write(packet protocol.Packet) {
 	}
 }
 
+// discard drops any packets written instead of sending them
+// for when there isn't a server.
+func (n *networkManager) discard() {
+	select {
+	case n.closeChan <- struct{}{}:
+	default:
+	}
+}
+
 func (n *networkManager) Close() {
 	if n.conn == nil {
 		return
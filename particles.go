@@ -0,0 +1,196 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/thinkofdeath/steven/render"
+)
+
+// particle is a single camera facing quad that moves
+// on its own until it expires.
+type particle struct {
+	x, y, z    float64
+	vx, vy, vz float64
+	gravity    float64
+	drag       float64
+
+	age, maxAge float64
+	size        float64
+	// Scales the particle from 0 to size over the first
+	// part of its life
+	grow bool
+
+	r, g, b byte
+	texture render.TextureInfo
+}
+
+// particleManager ticks and draws all particles using a single
+// model which is rebuilt every frame.
+type particleManager struct {
+	particles []*particle
+	model     *render.Model
+}
+
+func (pm *particleManager) free() {
+	pm.particles = nil
+	if pm.model != nil {
+		pm.model.Free()
+		pm.model = nil
+	}
+}
+
+func (pm *particleManager) add(p *particle) {
+	pm.particles = append(pm.particles, p)
+}
+
+// spawnCrits creates the particles vanilla creates around an
+// entity when it takes a critical hit.
+func (pm *particleManager) spawnCrits(e Entity) {
+	pc, ok := e.(PositionComponent)
+	if !ok {
+		return
+	}
+	x, y, z := pc.Position()
+	width, height := 0.6, 1.8
+	if s, ok := e.(SizeComponent); ok {
+		b := s.Bounds()
+		width = float64(b.Max.X() - b.Min.X())
+		height = float64(b.Max.Y() - b.Min.Y())
+	}
+	tex := render.RelativeTexture(render.GetTexture("particle/particles"), 128, 128).
+		Sub(8, 32, 8, 8)
+	// Vanilla's emitter runs for 3 ticks trying to spawn
+	// 16 particles a tick
+	for i := 0; i < 16*3; i++ {
+		dx := rand.Float64()*2 - 1
+		dy := rand.Float64()*2 - 1
+		dz := rand.Float64()*2 - 1
+		if dx*dx+dy*dy+dz*dz > 1 {
+			continue
+		}
+		col := byte(255 * (rand.Float64()*0.3 + 0.6))
+		pm.add(&particle{
+			x:       x + dx*width/4,
+			y:       y + height/2 + dy*height/4,
+			z:       z + dz*width/4,
+			vx:      dx * 0.4,
+			vy:      (dy + 0.2) * 0.4,
+			vz:      dz * 0.4,
+			gravity: 0.05,
+			drag:    0.7,
+			maxAge:  3 * math.Floor(6/(rand.Float64()*0.8+0.6)),
+			size:    0.1 * (rand.Float64()*0.5 + 0.5) * 2 * 0.75,
+			grow:    true,
+			r:       col,
+			g:       col,
+			b:       col,
+			texture: tex,
+		})
+	}
+}
+
+func (pm *particleManager) tick(delta float64) {
+	alive := pm.particles[:0]
+	ticks := delta / 3
+	for _, p := range pm.particles {
+		p.age += delta
+		if p.age >= p.maxAge {
+			continue
+		}
+		p.vy -= p.gravity * ticks
+		p.x += p.vx * ticks
+		p.y += p.vy * ticks
+		p.z += p.vz * ticks
+		drag := math.Pow(p.drag, ticks)
+		p.vx *= drag
+		p.vy *= drag
+		p.vz *= drag
+		alive = append(alive, p)
+	}
+	for i := len(alive); i < len(pm.particles); i++ {
+		pm.particles[i] = nil
+	}
+	pm.particles = alive
+
+	if len(pm.particles) == 0 {
+		if pm.model != nil {
+			pm.model.Free()
+			pm.model = nil
+		}
+		return
+	}
+	verts := pm.vertices()
+	if pm.model == nil {
+		pm.model = render.NewModel([][]*render.ModelVertex{verts})
+	} else {
+		pm.model.Verts = verts
+		pm.model.Refresh()
+	}
+	bx, by, bz := int(math.Floor(render.Camera.X)), int(math.Floor(render.Camera.Y)), int(math.Floor(render.Camera.Z))
+	pm.model.BlockLight = float32(chunkMap.BlockLight(bx, by, bz))
+	pm.model.SkyLight = float32(chunkMap.SkyLight(bx, by, bz))
+}
+
+// vertices returns a quad for each particle facing the camera.
+func (pm *particleManager) vertices() []*render.ModelVertex {
+	view := Client.viewVector()
+	right := view.Cross(mgl32.Vec3{0, 1, 0})
+	if right.Len() < 0.001 {
+		right = mgl32.Vec3{1, 0, 0}
+	}
+	right = right.Normalize()
+	up := right.Cross(view).Normalize()
+
+	verts := make([]*render.ModelVertex, 0, len(pm.particles)*8)
+	for _, p := range pm.particles {
+		size := p.size
+		if p.grow {
+			size *= math.Min(p.age/p.maxAge*32, 1)
+		}
+		center := mgl32.Vec3{float32(p.x), float32(p.y), float32(p.z)}
+		r := right.Mul(float32(size))
+		u := up.Mul(float32(size))
+		corners := [4]mgl32.Vec3{
+			center.Sub(r).Sub(u),
+			center.Sub(r).Add(u),
+			center.Add(r).Sub(u),
+			center.Add(r).Add(u),
+		}
+		texCoords := [4][2]float64{{0, 1}, {0, 0}, {1, 1}, {1, 0}}
+		// Drawn twice so that it can be seen from both sides
+		for _, order := range [2][4]int{{0, 1, 2, 3}, {2, 3, 0, 1}} {
+			for _, i := range order {
+				c := corners[i]
+				verts = append(verts, &render.ModelVertex{
+					X:        c.X(),
+					Y:        -c.Y(),
+					Z:        c.Z(),
+					Texture:  p.texture,
+					TextureX: texCoords[i][0],
+					TextureY: texCoords[i][1],
+					R:        p.r,
+					G:        p.g,
+					B:        p.b,
+					A:        255,
+				})
+			}
+		}
+	}
+	return verts
+}
@@ -0,0 +1,747 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"math"
+	"time"
+
+	"github.com/thinkofdeath/steven/type/direction"
+	"github.com/thinkofdeath/steven/type/vmath"
+)
+
+// Constants used by vanilla for player movement. All values
+// are per tick.
+const (
+	physicsTickTime = time.Second / 20
+
+	playerWidth     = 0.6
+	playerBoxHeight = 1.8
+	playerStep      = 0.6
+
+	physicsGravity     = 0.08
+	physicsDrag        = 0.98
+	physicsAirFriction = 0.02
+	physicsSprintAir   = 0.006
+	physicsJumpSpeed   = 0.42
+	physicsJumpDelay   = 10
+	physicsLadderSpeed = 0.15
+	physicsLiquidSpeed = 0.02
+	physicsWaterPush   = 0.014
+	// Lava pushes much more weakly than water
+	physicsLavaPush = 0.0023333333333333335
+)
+
+// physicsInput is the player's movement input for a single
+// tick.
+type physicsInput struct {
+	// The movement relative to where the player is facing
+	// already scaled for sneaking
+	forward, strafe float64
+	// The player's yaw in vanilla's radians
+	yaw float64
+
+	jump, sneak         bool
+	sprinting, flying   bool
+	noClip              bool
+	walkSpeed, flySpeed float64
+}
+
+// playerPhysics simulates the player's movement in the same way
+// vanilla does, once every tick, so that servers agree with where
+// we moved to. Rendering interpolates between the previous and
+// current positions.
+type playerPhysics struct {
+	x, y, z                   float64
+	prevX, prevY, prevZ       float64
+	motionX, motionY, motionZ float64
+
+	onGround             bool
+	collidedHorizontally bool
+	inWater, inLava      bool
+	inWeb                bool
+	jumpTicks            int
+
+	lastTick time.Time
+}
+
+// teleport moves the player without interpolating between the
+// old and new positions.
+func (p *playerPhysics) teleport(x, y, z float64) {
+	p.x, p.y, p.z = x, y, z
+	p.prevX, p.prevY, p.prevZ = x, y, z
+	p.onGround = len(collisionBoxes(p.box().offset(0, -0.001, 0), nil)) > 0
+}
+
+// position returns the player's position interpolated between
+// the last two ticks.
+func (p *playerPhysics) position() (x, y, z float64) {
+	t := float64(time.Since(p.lastTick)) / float64(physicsTickTime)
+	if t > 1 {
+		t = 1
+	} else if t < 0 {
+		t = 0
+	}
+	return p.prevX + (p.x-p.prevX)*t,
+		p.prevY + (p.y-p.prevY)*t,
+		p.prevZ + (p.z-p.prevZ)*t
+}
+
+func (p *playerPhysics) box() physicsBox {
+	return physicsBox{
+		minX: p.x - playerWidth/2, minY: p.y, minZ: p.z - playerWidth/2,
+		maxX: p.x + playerWidth/2, maxY: p.y + playerBoxHeight, maxZ: p.z + playerWidth/2,
+	}
+}
+
+// tick moves the player by a single tick.
+func (p *playerPhysics) tick(in physicsInput, border *worldBorder) {
+	p.lastTick = time.Now()
+	p.prevX, p.prevY, p.prevZ = p.x, p.y, p.z
+
+	if p.jumpTicks > 0 {
+		p.jumpTicks--
+	}
+	// Very small movements are dropped by vanilla
+	if math.Abs(p.motionX) < 0.005 {
+		p.motionX = 0
+	}
+	if math.Abs(p.motionY) < 0.005 {
+		p.motionY = 0
+	}
+	if math.Abs(p.motionZ) < 0.005 {
+		p.motionZ = 0
+	}
+
+	p.updateLiquids()
+
+	if in.flying {
+		if in.sneak {
+			p.motionY -= in.flySpeed * 3
+		}
+		if in.jump {
+			p.motionY += in.flySpeed * 3
+		}
+	} else if in.jump {
+		switch {
+		case p.inWater || p.inLava:
+			p.motionY += 0.04
+		case p.onGround && p.jumpTicks == 0:
+			p.motionY = physicsJumpSpeed
+			if in.sprinting {
+				p.motionX -= math.Sin(in.yaw) * 0.2
+				p.motionZ += math.Cos(in.yaw) * 0.2
+			}
+			p.jumpTicks = physicsJumpDelay
+		}
+	} else {
+		p.jumpTicks = 0
+	}
+
+	strafe, forward := in.strafe*physicsDrag, in.forward*physicsDrag
+	if in.flying {
+		motionY := p.motionY
+		airSpeed := in.flySpeed
+		if in.sprinting {
+			airSpeed *= 2
+		}
+		p.moveWithHeading(in, strafe, forward, airSpeed, border)
+		p.motionY = motionY * 0.6
+	} else {
+		airSpeed := physicsAirFriction
+		if in.sprinting {
+			airSpeed += physicsSprintAir
+		}
+		p.moveWithHeading(in, strafe, forward, airSpeed, border)
+	}
+}
+
+// moveWithHeading applies the player's input and the effects of
+// the blocks around them to their motion and moves them.
+func (p *playerPhysics) moveWithHeading(in physicsInput, strafe, forward, airSpeed float64, border *worldBorder) {
+	if !in.flying && (p.inWater || p.inLava) {
+		drag := 0.8
+		if p.inLava {
+			drag = 0.5
+		}
+		startY := p.y
+		p.moveFlying(in.yaw, strafe, forward, physicsLiquidSpeed)
+		p.move(in, p.motionX, p.motionY, p.motionZ, border)
+		p.motionX *= drag
+		p.motionY *= drag
+		p.motionZ *= drag
+		p.motionY -= 0.02
+		// Allows the player to jump out of liquids onto
+		// the block in front of them
+		if p.collidedHorizontally && p.isFree(p.motionX, p.motionY+0.6-p.y+startY, p.motionZ) {
+			p.motionY = 0.3
+		}
+		return
+	}
+
+	friction := 0.91
+	if p.onGround {
+		friction = blockSlipperiness(chunkMap.Block(
+			int(math.Floor(p.x)), int(math.Floor(p.y-1)), int(math.Floor(p.z)),
+		)) * 0.91
+	}
+	speed := airSpeed
+	if p.onGround {
+		moveSpeed := in.walkSpeed
+		if in.sprinting {
+			moveSpeed *= 1.3
+		}
+		speed = moveSpeed * (0.16277136 / (friction * friction * friction))
+	}
+	p.moveFlying(in.yaw, strafe, forward, speed)
+
+	// Only spectators can't climb
+	onLadder := !in.noClip && p.onLadder()
+	if onLadder {
+		p.motionX = clampFloat(p.motionX, -physicsLadderSpeed, physicsLadderSpeed)
+		p.motionZ = clampFloat(p.motionZ, -physicsLadderSpeed, physicsLadderSpeed)
+		if p.motionY < -physicsLadderSpeed {
+			p.motionY = -physicsLadderSpeed
+		}
+		// Sneaking holds the player in place
+		if in.sneak && p.motionY < 0 {
+			p.motionY = 0
+		}
+	}
+
+	p.move(in, p.motionX, p.motionY, p.motionZ, border)
+
+	if p.collidedHorizontally && onLadder {
+		p.motionY = 0.2
+	}
+	p.motionY -= physicsGravity
+	p.motionY *= physicsDrag
+	p.motionX *= friction
+	p.motionZ *= friction
+}
+
+// moveFlying adds the input to the player's motion in the
+// direction they are facing.
+func (p *playerPhysics) moveFlying(yaw, strafe, forward, friction float64) {
+	f := strafe*strafe + forward*forward
+	if f < 1.0e-4 {
+		return
+	}
+	f = math.Sqrt(f)
+	if f < 1 {
+		f = 1
+	}
+	f = friction / f
+	strafe *= f
+	forward *= f
+	s, c := math.Sin(yaw), math.Cos(yaw)
+	p.motionX += strafe*c - forward*s
+	p.motionZ += forward*c + strafe*s
+}
+
+// move moves the player by the offset stopping at any blocks
+// in the way and stepping up small ledges.
+func (p *playerPhysics) move(in physicsInput, dx, dy, dz float64, border *worldBorder) {
+	if in.noClip {
+		p.x += dx
+		p.y += dy
+		p.z += dz
+		p.onGround = false
+		p.collidedHorizontally = false
+		return
+	}
+
+	if p.inWeb {
+		p.inWeb = false
+		dx *= 0.25
+		dy *= 0.05
+		dz *= 0.25
+		p.motionX, p.motionY, p.motionZ = 0, 0, 0
+	}
+
+	box := p.box()
+	startX, startY, startZ := dx, dy, dz
+
+	// Sneaking stops the player from walking off of the edge
+	// of blocks
+	if p.onGround && in.sneak && !in.flying {
+		const step = 0.05
+		shrink := func(d float64) float64 {
+			switch {
+			case d < step && d >= -step:
+				return 0
+			case d > 0:
+				return d - step
+			}
+			return d + step
+		}
+		for dx != 0 && len(collisionBoxes(box.offset(dx, -1, 0), border)) == 0 {
+			dx = shrink(dx)
+			startX = dx
+		}
+		for dz != 0 && len(collisionBoxes(box.offset(0, -1, dz), border)) == 0 {
+			dz = shrink(dz)
+			startZ = dz
+		}
+		for dx != 0 && dz != 0 && len(collisionBoxes(box.offset(dx, -1, dz), border)) == 0 {
+			dx = shrink(dx)
+			dz = shrink(dz)
+			startX, startZ = dx, dz
+		}
+	}
+
+	boxes := collisionBoxes(box.expand(dx, dy, dz), border)
+	moved := box
+	for _, b := range boxes {
+		dy = b.yOffset(moved, dy)
+	}
+	moved = moved.offset(0, dy, 0)
+	for _, b := range boxes {
+		dx = b.xOffset(moved, dx)
+	}
+	moved = moved.offset(dx, 0, 0)
+	for _, b := range boxes {
+		dz = b.zOffset(moved, dz)
+	}
+	moved = moved.offset(0, 0, dz)
+
+	// Step up blocks such as slabs and stairs if it would
+	// let the player move further
+	canStep := p.onGround || (startY != dy && startY < 0)
+	if canStep && (startX != dx || startZ != dz) {
+		boxes := collisionBoxes(box.expand(startX, playerStep, startZ), border)
+
+		// Step up before moving across
+		upBox := box
+		upY := playerStep
+		combined := upBox.expand(startX, 0, startZ)
+		for _, b := range boxes {
+			upY = b.yOffset(combined, upY)
+		}
+		upBox = upBox.offset(0, upY, 0)
+		upX := startX
+		for _, b := range boxes {
+			upX = b.xOffset(upBox, upX)
+		}
+		upBox = upBox.offset(upX, 0, 0)
+		upZ := startZ
+		for _, b := range boxes {
+			upZ = b.zOffset(upBox, upZ)
+		}
+		upBox = upBox.offset(0, 0, upZ)
+
+		// Step up using only the player's current position
+		stepBox := box
+		stepY := playerStep
+		for _, b := range boxes {
+			stepY = b.yOffset(stepBox, stepY)
+		}
+		stepBox = stepBox.offset(0, stepY, 0)
+		stepX := startX
+		for _, b := range boxes {
+			stepX = b.xOffset(stepBox, stepX)
+		}
+		stepBox = stepBox.offset(stepX, 0, 0)
+		stepZ := startZ
+		for _, b := range boxes {
+			stepZ = b.zOffset(stepBox, stepZ)
+		}
+		stepBox = stepBox.offset(0, 0, stepZ)
+
+		var sx, sy, sz float64
+		var stepped physicsBox
+		if upX*upX+upZ*upZ > stepX*stepX+stepZ*stepZ {
+			sx, sy, sz, stepped = upX, -upY, upZ, upBox
+		} else {
+			sx, sy, sz, stepped = stepX, -stepY, stepZ, stepBox
+		}
+		// Move back down onto the block
+		for _, b := range boxes {
+			sy = b.yOffset(stepped, sy)
+		}
+		stepped = stepped.offset(0, sy, 0)
+
+		if dx*dx+dz*dz < sx*sx+sz*sz {
+			dx, dz = sx, sz
+			dy = stepped.minY - box.minY
+			moved = stepped
+		}
+	}
+
+	p.x = (moved.minX + moved.maxX) / 2
+	p.y = moved.minY
+	p.z = (moved.minZ + moved.maxZ) / 2
+
+	p.collidedHorizontally = startX != dx || startZ != dz
+	p.onGround = startY != dy && startY < 0
+	if startX != dx {
+		p.motionX = 0
+	}
+	if startZ != dz {
+		p.motionZ = 0
+	}
+	if startY != dy {
+		landedOn := chunkMap.Block(int(math.Floor(p.x)), int(math.Floor(p.y-0.2)), int(math.Floor(p.z)))
+		// Slime bounces the player back up unless they are
+		// sneaking
+		if landedOn.Is(Blocks.Slime) && !in.sneak && p.motionY < 0 {
+			p.motionY = -p.motionY
+		} else {
+			p.motionY = 0
+		}
+	}
+	p.blockCollisions(in)
+}
+
+// blockCollisions applies the effects of the blocks the player
+// is inside of.
+func (p *playerPhysics) blockCollisions(in physicsInput) {
+	box := p.box().grow(-0.001, -0.001, -0.001)
+	forEachBlock(box, func(x, y, z int, b Block) {
+		switch {
+		case b.Is(Blocks.Web):
+			p.inWeb = true
+		case b.Is(Blocks.SoulSand):
+			p.motionX *= 0.4
+			p.motionZ *= 0.4
+		}
+	})
+	if p.onGround && !in.sneak && math.Abs(p.motionY) < 0.1 {
+		below := chunkMap.Block(int(math.Floor(p.x)), int(math.Floor(p.y-0.2)), int(math.Floor(p.z)))
+		if below.Is(Blocks.Slime) {
+			f := 0.4 + math.Abs(p.motionY)*0.2
+			p.motionX *= f
+			p.motionZ *= f
+		}
+	}
+}
+
+// updateLiquids checks whether the player is in water or lava
+// and pushes them along with any currents.
+func (p *playerPhysics) updateLiquids() {
+	box := p.box()
+	var fx, fy, fz float64
+	p.inWater = false
+	forEachBlock(box.grow(-0.001, -0.401, -0.001), func(x, y, z int, b Block) {
+		l, ok := b.(*blockLiquid)
+		if !ok || l.Lava {
+			return
+		}
+		if math.Ceil(box.maxY-0.401) < float64(y+1)-liquidHeight(l) {
+			return
+		}
+		p.inWater = true
+		x1, y1, z1 := liquidFlow(x, y, z, false)
+		fx += x1
+		fy += y1
+		fz += z1
+	})
+	p.addCurrent(fx, fy, fz, physicsWaterPush)
+
+	fx, fy, fz = 0, 0, 0
+	p.inLava = false
+	forEachBlock(box.grow(-0.1, -0.4, -0.1), func(x, y, z int, b Block) {
+		if l, ok := b.(*blockLiquid); ok && l.Lava {
+			p.inLava = true
+			x1, y1, z1 := liquidFlow(x, y, z, true)
+			fx += x1
+			fy += y1
+			fz += z1
+		}
+	})
+	p.addCurrent(fx, fy, fz, physicsLavaPush)
+}
+
+func (p *playerPhysics) addCurrent(fx, fy, fz, strength float64) {
+	l := math.Sqrt(fx*fx + fy*fy + fz*fz)
+	if l < 1.0e-4 {
+		return
+	}
+	p.motionX += fx / l * strength
+	p.motionY += fy / l * strength
+	p.motionZ += fz / l * strength
+}
+
+// isFree returns whether the player could be moved by the offset
+// without ending up inside a block or a liquid.
+func (p *playerPhysics) isFree(dx, dy, dz float64) bool {
+	box := p.box().offset(dx, dy, dz)
+	if len(collisionBoxes(box, nil)) > 0 {
+		return false
+	}
+	free := true
+	forEachBlock(box, func(x, y, z int, b Block) {
+		if _, ok := b.(*blockLiquid); ok {
+			free = false
+		}
+	})
+	return free
+}
+
+func (p *playerPhysics) onLadder() bool {
+	b := chunkMap.Block(int(math.Floor(p.x)), int(math.Floor(p.y)), int(math.Floor(p.z)))
+	return b.Is(Blocks.Ladder) || b.Is(Blocks.Vine)
+}
+
+// blockSlipperiness returns how slippery the block is to walk on.
+func blockSlipperiness(b Block) float64 {
+	switch {
+	case b.Is(Blocks.Ice), b.Is(Blocks.PackedIce):
+		return 0.98
+	case b.Is(Blocks.Slime):
+		return 0.8
+	}
+	return 0.6
+}
+
+// liquidHeight returns the fraction of the block the liquid
+// leaves empty.
+func liquidHeight(l *blockLiquid) float64 {
+	level := l.Level
+	if level >= 8 {
+		level = 0
+	}
+	return float64(level+1) / 9
+}
+
+// liquidDecay returns how far the liquid at the location has
+// flowed from its source or -1 if the block isn't the same
+// type of liquid.
+func liquidDecay(x, y, z int, lava bool) int {
+	l, ok := chunkMap.Block(x, y, z).(*blockLiquid)
+	if !ok || l.Lava != lava {
+		return -1
+	}
+	if l.Level >= 8 {
+		return 0
+	}
+	return l.Level
+}
+
+// liquidFlow returns the normalized direction the liquid at the
+// location is flowing in.
+func liquidFlow(x, y, z int, lava bool) (fx, fy, fz float64) {
+	decay := liquidDecay(x, y, z, lava)
+	for _, d := range [...]direction.Type{direction.North, direction.South, direction.West, direction.East} {
+		ox, _, oz := d.Offset()
+		bx, bz := x+ox, z+oz
+		other := liquidDecay(bx, y, bz, lava)
+		var k int
+		if other < 0 {
+			if chunkMap.Block(bx, y, bz).Collidable() {
+				continue
+			}
+			other = liquidDecay(bx, y-1, bz, lava)
+			if other < 0 {
+				continue
+			}
+			k = other - (decay - 8)
+		} else {
+			k = other - decay
+		}
+		fx += float64(ox * k)
+		fz += float64(oz * k)
+	}
+
+	// Falling liquids pull entities down next to walls
+	if l, ok := chunkMap.Block(x, y, z).(*blockLiquid); ok && l.Level >= 8 {
+		for _, d := range [...]direction.Type{direction.North, direction.South, direction.West, direction.East} {
+			ox, _, oz := d.Offset()
+			if downwardCurrent(x+ox, y, z+oz, lava) || downwardCurrent(x+ox, y+1, z+oz, lava) {
+				fx, fy, fz = normalizeFlow(fx, fy, fz)
+				fy -= 6
+				break
+			}
+		}
+	}
+	return normalizeFlow(fx, fy, fz)
+}
+
+func downwardCurrent(x, y, z int, lava bool) bool {
+	b := chunkMap.Block(x, y, z)
+	if l, ok := b.(*blockLiquid); ok && l.Lava == lava {
+		return false
+	}
+	return b.Collidable() && !b.Is(Blocks.Ice)
+}
+
+func normalizeFlow(x, y, z float64) (float64, float64, float64) {
+	l := math.Sqrt(x*x + y*y + z*z)
+	if l < 1.0e-4 {
+		return 0, 0, 0
+	}
+	return x / l, y / l, z / l
+}
+
+func clampFloat(x, min, max float64) float64 {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
+
+// forEachBlock calls the function for every block that the box
+// touches.
+func forEachBlock(box physicsBox, f func(x, y, z int, b Block)) {
+	minX, minY, minZ := int(math.Floor(box.minX)), int(math.Floor(box.minY)), int(math.Floor(box.minZ))
+	maxX, maxY, maxZ := int(math.Floor(box.maxX)), int(math.Floor(box.maxY)), int(math.Floor(box.maxZ))
+	for y := minY; y <= maxY; y++ {
+		for z := minZ; z <= maxZ; z++ {
+			for x := minX; x <= maxX; x++ {
+				f(x, y, z, chunkMap.Block(x, y, z))
+			}
+		}
+	}
+}
+
+// collisionBoxes returns the bounds of every block that
+// intersects the box. The world border is included if
+// passed and the box is inside of it.
+func collisionBoxes(box physicsBox, border *worldBorder) (out []physicsBox) {
+	// Start a block lower to catch blocks taller than
+	// a block such as fences
+	forEachBlock(box.expand(0, -1, 0), func(x, y, z int, b Block) {
+		if !b.Collidable() {
+			return
+		}
+		for _, bb := range b.CollisionBounds() {
+			bb := boxFromAABB(bb).offset(float64(x), float64(y), float64(z))
+			if bb.intersects(box) {
+				out = append(out, bb)
+			}
+		}
+	})
+	if border != nil && border.Inside((box.minX+box.maxX)/2, (box.minZ+box.maxZ)/2) {
+		for _, bb := range border.collisionBounds() {
+			bb := boxFromAABB(bb)
+			if bb.intersects(box) {
+				out = append(out, bb)
+			}
+		}
+	}
+	return out
+}
+
+// physicsBox is a double precision bounding box matching
+// vanilla's.
+type physicsBox struct {
+	minX, minY, minZ float64
+	maxX, maxY, maxZ float64
+}
+
+func boxFromAABB(a vmath.AABB) physicsBox {
+	return physicsBox{
+		minX: float64(a.Min.X()), minY: float64(a.Min.Y()), minZ: float64(a.Min.Z()),
+		maxX: float64(a.Max.X()), maxY: float64(a.Max.Y()), maxZ: float64(a.Max.Z()),
+	}
+}
+
+func (b physicsBox) offset(x, y, z float64) physicsBox {
+	return physicsBox{
+		minX: b.minX + x, minY: b.minY + y, minZ: b.minZ + z,
+		maxX: b.maxX + x, maxY: b.maxY + y, maxZ: b.maxZ + z,
+	}
+}
+
+// expand extends the box in the direction of the offset.
+func (b physicsBox) expand(x, y, z float64) physicsBox {
+	if x < 0 {
+		b.minX += x
+	} else {
+		b.maxX += x
+	}
+	if y < 0 {
+		b.minY += y
+	} else {
+		b.maxY += y
+	}
+	if z < 0 {
+		b.minZ += z
+	} else {
+		b.maxZ += z
+	}
+	return b
+}
+
+// grow grows the box on every side, negative values shrink it.
+func (b physicsBox) grow(x, y, z float64) physicsBox {
+	return physicsBox{
+		minX: b.minX - x, minY: b.minY - y, minZ: b.minZ - z,
+		maxX: b.maxX + x, maxY: b.maxY + y, maxZ: b.maxZ + z,
+	}
+}
+
+func (b physicsBox) intersects(o physicsBox) bool {
+	return o.maxX > b.minX && o.minX < b.maxX &&
+		o.maxY > b.minY && o.minY < b.maxY &&
+		o.maxZ > b.minZ && o.minZ < b.maxZ
+}
+
+// xOffset limits how far the other box can move along the x
+// axis before hitting this box.
+func (b physicsBox) xOffset(o physicsBox, d float64) float64 {
+	if o.maxY <= b.minY || o.minY >= b.maxY || o.maxZ <= b.minZ || o.minZ >= b.maxZ {
+		return d
+	}
+	if d > 0 && o.maxX <= b.minX {
+		if m := b.minX - o.maxX; m < d {
+			d = m
+		}
+	} else if d < 0 && o.minX >= b.maxX {
+		if m := b.maxX - o.minX; m > d {
+			d = m
+		}
+	}
+	return d
+}
+
+// yOffset limits how far the other box can move along the y
+// axis before hitting this box.
+func (b physicsBox) yOffset(o physicsBox, d float64) float64 {
+	if o.maxX <= b.minX || o.minX >= b.maxX || o.maxZ <= b.minZ || o.minZ >= b.maxZ {
+		return d
+	}
+	if d > 0 && o.maxY <= b.minY {
+		if m := b.minY - o.maxY; m < d {
+			d = m
+		}
+	} else if d < 0 && o.minY >= b.maxY {
+		if m := b.maxY - o.minY; m > d {
+			d = m
+		}
+	}
+	return d
+}
+
+// zOffset limits how far the other box can move along the z
+// axis before hitting this box.
+func (b physicsBox) zOffset(o physicsBox, d float64) float64 {
+	if o.maxX <= b.minX || o.minX >= b.maxX || o.maxY <= b.minY || o.minY >= b.maxY {
+		return d
+	}
+	if d > 0 && o.maxZ <= b.minZ {
+		if m := b.minZ - o.maxZ; m < d {
+			d = m
+		}
+	} else if d < 0 && o.minZ >= b.maxZ {
+		if m := b.maxZ - o.minZ; m > d {
+			d = m
+		}
+	}
+	return d
+}
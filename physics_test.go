@@ -0,0 +1,238 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"math"
+	"testing"
+
+	"github.com/thinkofdeath/steven/type/direction"
+	"github.com/thinkofdeath/steven/world"
+)
+
+// newPhysicsTestWorld replaces the world with empty chunks
+// covering -32 to 31 on the x and z axis without any render
+// state.
+func newPhysicsTestWorld() {
+	if len(allBlocks) == 0 {
+		flattenBlocks()
+	}
+	chunkMap = newClientWorld()
+	for x := -2; x < 2; x++ {
+		for z := -2; z < 2; z++ {
+			pos := chunkPosition{x, z}
+			data := world.NewChunk(x, z)
+			chunkMap.chunks[pos] = &chunk{chunkPosition: pos, data: data, hidden: true}
+			chunkMap.data.AddChunk(data)
+		}
+	}
+}
+
+// fillTestBlocks sets every block between the two corners,
+// inclusive, without updating lighting.
+func fillTestBlocks(b Block, x1, y1, z1, x2, y2, z2 int) {
+	for y := y1; y <= y2; y++ {
+		for z := z1; z <= z2; z++ {
+			for x := x1; x <= x2; x++ {
+				chunkMap.chunks[chunkPosition{x >> 4, z >> 4}].data.SetBlock(b.SID(), x&0xF, y, z&0xF)
+			}
+		}
+	}
+}
+
+// walkInput walks towards positive z.
+var walkInput = physicsInput{
+	forward:   1,
+	walkSpeed: defaultWalkingSpeed,
+	flySpeed:  defaultFlyingSpeed,
+}
+
+// near returns whether the values are equal apart from
+// rounding errors.
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func runPhysics(p *playerPhysics, in physicsInput, ticks int) {
+	for i := 0; i < ticks; i++ {
+		p.tick(in, nil)
+	}
+}
+
+func TestPhysicsJumpApex(t *testing.T) {
+	newPhysicsTestWorld()
+	fillTestBlocks(Blocks.Stone.Base, -2, 63, -2, 2, 63, 2)
+
+	var p playerPhysics
+	p.teleport(0.5, 64, 0.5)
+	if !p.onGround {
+		t.Fatal("player isn't on the ground")
+	}
+	p.tick(physicsInput{jump: true, walkSpeed: defaultWalkingSpeed}, nil)
+	apex := p.y
+	for i := 0; i < 30; i++ {
+		p.tick(physicsInput{walkSpeed: defaultWalkingSpeed}, nil)
+		apex = math.Max(apex, p.y)
+	}
+	if h := apex - 64; h < 1.24 || h > 1.26 {
+		t.Errorf("jumped %f blocks high, wanted 1.249", h)
+	}
+	if !near(p.y, 64) || !p.onGround {
+		t.Errorf("player didn't land, at y %f", p.y)
+	}
+}
+
+func TestPhysicsIce(t *testing.T) {
+	newPhysicsTestWorld()
+	fillTestBlocks(Blocks.Stone.Base, -2, 63, -32, 2, 63, -1)
+	fillTestBlocks(Blocks.Ice.Base, -2, 63, 0, 2, 63, 31)
+
+	var stone, ice playerPhysics
+	stone.teleport(0.5, 64, -30.5)
+	ice.teleport(0.5, 64, 0.5)
+
+	stone.tick(walkInput, nil)
+	ice.tick(walkInput, nil)
+	if sd, id := stone.z-stone.prevZ, ice.z-ice.prevZ; id >= sd/2 {
+		t.Errorf("speeding up on ice (%f) should be slower than on stone (%f)", id, sd)
+	}
+
+	runPhysics(&stone, walkInput, 40)
+	runPhysics(&ice, walkInput, 40)
+	if sd := stone.z - stone.prevZ; math.Abs(sd-0.2158) > 0.001 {
+		t.Errorf("walking speed on stone is %f, wanted 0.2158", sd)
+	}
+
+	stoneZ, iceZ := stone.z, ice.z
+	runPhysics(&stone, physicsInput{walkSpeed: defaultWalkingSpeed}, 40)
+	runPhysics(&ice, physicsInput{walkSpeed: defaultWalkingSpeed}, 40)
+	if slide := stone.z - stoneZ; slide > 0.5 {
+		t.Errorf("slid %f blocks on stone", slide)
+	}
+	if slide := ice.z - iceZ; slide < 1 {
+		t.Errorf("only slid %f blocks on ice", slide)
+	}
+}
+
+func TestPhysicsSoulSand(t *testing.T) {
+	newPhysicsTestWorld()
+	fillTestBlocks(Blocks.Stone.Base, -2, 63, -32, -1, 63, 31)
+	fillTestBlocks(Blocks.SoulSand.Base, 1, 63, -32, 2, 63, 31)
+
+	var stone, sand playerPhysics
+	stone.teleport(-1, 64, -30.5)
+	sand.teleport(2, 63.875, -30.5)
+	if !sand.onGround {
+		t.Fatal("player isn't on the soul sand")
+	}
+	runPhysics(&stone, walkInput, 40)
+	runPhysics(&sand, walkInput, 40)
+	sd, sa := stone.z-stone.prevZ, sand.z-sand.prevZ
+	if sa <= 0 || sa > sd*0.7 {
+		t.Errorf("walking speed on soul sand is %f compared to %f on stone", sa, sd)
+	}
+}
+
+func TestPhysicsLadder(t *testing.T) {
+	newPhysicsTestWorld()
+	fillTestBlocks(Blocks.Stone.Base, -2, 63, -2, 2, 63, 2)
+	ladder := Blocks.Ladder.Base.Set("facing", direction.South)
+	fillTestBlocks(ladder, 0, 64, 0, 0, 70, 0)
+
+	// Walking into the ladder climbs it
+	var p playerPhysics
+	p.teleport(0.5, 64, 0.5)
+	climb := walkInput
+	climb.yaw = math.Pi
+	runPhysics(&p, climb, 20)
+	if p.y < 65.5 || p.y > 70 {
+		t.Errorf("climbed to %f, wanted about 66.2", p.y)
+	}
+
+	// Sneaking whilst flying holds on to the ladder
+	p.teleport(0.5, 66, 0.5)
+	p.motionX, p.motionY, p.motionZ = 0, 0, 0
+	fly := physicsInput{sneak: true, flying: true, flySpeed: defaultFlyingSpeed}
+	runPhysics(&p, fly, 10)
+	if !near(p.y, 66) {
+		t.Errorf("flying sneaking player moved to %f on the ladder", p.y)
+	}
+	p.teleport(1.5, 66, 1.5)
+	runPhysics(&p, fly, 10)
+	if p.y >= 66 {
+		t.Errorf("flying sneaking player didn't descend away from the ladder")
+	}
+}
+
+func TestPhysicsWeb(t *testing.T) {
+	newPhysicsTestWorld()
+	fillTestBlocks(Blocks.Stone.Base, -2, 63, -32, 2, 63, 31)
+	fillTestBlocks(Blocks.Web.Base, 1, 64, -32, 2, 65, 31)
+
+	var free, web playerPhysics
+	free.teleport(-1, 64, -30.5)
+	web.teleport(2, 64, -30.5)
+	runPhysics(&free, walkInput, 40)
+	runPhysics(&web, walkInput, 40)
+	fd, wd := free.z+30.5, web.z+30.5
+	if wd <= 0 || wd > fd/4 {
+		t.Errorf("walked %f blocks through webs compared to %f", wd, fd)
+	}
+}
+
+func TestPhysicsStep(t *testing.T) {
+	newPhysicsTestWorld()
+	fillTestBlocks(Blocks.Stone.Base, -2, 63, -32, 2, 63, 31)
+	slab := Blocks.StoneSlab.Base.Set("half", slabBottom)
+	fillTestBlocks(slab, -2, 64, -15, -1, 64, 31)
+	fillTestBlocks(Blocks.Stone.Base, 1, 64, -15, 2, 64, 31)
+
+	var half, full playerPhysics
+	half.teleport(-1, 64, -18.5)
+	full.teleport(2, 64, -18.5)
+	runPhysics(&half, walkInput, 30)
+	runPhysics(&full, walkInput, 30)
+	if !near(half.y, 64.5) || half.z < -15 {
+		t.Errorf("didn't step onto the slab, at y %f z %f", half.y, half.z)
+	}
+	if !near(full.y, 64) || full.z > -15.3+1e-6 {
+		t.Errorf("stepped onto a full block, at y %f z %f", full.y, full.z)
+	}
+}
+
+func TestPhysicsSneakEdge(t *testing.T) {
+	newPhysicsTestWorld()
+	fillTestBlocks(Blocks.Stone.Base, -2, 63, -32, 2, 63, -10)
+
+	sneak := walkInput
+	sneak.forward = 0.3
+	sneak.sneak = true
+	var p playerPhysics
+	p.teleport(0.5, 64, -12.5)
+	runPhysics(&p, sneak, 100)
+	if !near(p.y, 64) || !p.onGround {
+		t.Fatalf("sneaking player fell off the edge to y %f", p.y)
+	}
+	if p.z > -8.7 || p.z < -9.5 {
+		t.Errorf("sneaking player stopped at z %f, wanted the edge", p.z)
+	}
+
+	// Walking doesn't stop at the edge
+	p.teleport(0.5, 64, -12.5)
+	runPhysics(&p, walkInput, 60)
+	if p.y >= 64 {
+		t.Errorf("walking player didn't fall off the edge")
+	}
+}
@@ -44,10 +44,14 @@ type playerListUI struct {
 	background [4]*ui.Image
 	entries    []*playerListUIEntry
 	scene      *scene.Type
+
+	header, footer                     *ui.Formatted
+	headerBackground, footerBackground *ui.Image
 }
 
 type playerListUIEntry struct {
-	text    *ui.Text
+	uuid    protocol.UUID
+	text    *ui.Formatted
 	icon    *ui.Image
 	iconHat *ui.Image
 	ping    *ui.Image
@@ -69,6 +73,40 @@ func (p *playerListUI) init() {
 		p.background[i].SetDraw(false)
 		p.scene.AddDrawable(p.background[i].Attach(ui.Top, ui.Center))
 	}
+
+	p.headerBackground = ui.NewImage(render.GetTexture("solid"), 0, 16, 0, 0, 0, 0, 1, 1, 0, 0, 0)
+	p.headerBackground.SetA(120)
+	p.headerBackground.SetDraw(false)
+	p.scene.AddDrawable(p.headerBackground.Attach(ui.Top, ui.Center))
+	p.header = ui.NewFormatted(format.Wrap(&format.TextComponent{}), 0, 0)
+	p.header.AttachTo(p.headerBackground)
+	p.scene.AddDrawable(p.header.Attach(ui.Middle, ui.Center))
+
+	p.footerBackground = ui.NewImage(render.GetTexture("solid"), 0, 16, 0, 0, 0, 0, 1, 1, 0, 0, 0)
+	p.footerBackground.SetA(120)
+	p.footerBackground.SetDraw(false)
+	p.scene.AddDrawable(p.footerBackground.Attach(ui.Top, ui.Center))
+	p.footer = ui.NewFormatted(format.Wrap(&format.TextComponent{}), 0, 0)
+	p.footer.AttachTo(p.footerBackground)
+	p.scene.AddDrawable(p.footer.Attach(ui.Middle, ui.Center))
+}
+
+// setHeaderFooter changes the text displayed above and below
+// the list. Empty components hide the text.
+func (p *playerListUI) setHeaderFooter(header, footer format.AnyComponent) {
+	set := func(f *ui.Formatted, b *ui.Image, val format.AnyComponent) {
+		if val.Value == nil {
+			val = format.Wrap(&format.TextComponent{})
+		}
+		f.Update(val)
+		w, h := f.Size()
+		b.SetWidth(w + 8)
+		b.SetHeight(h + 4)
+		b.SetDraw(f.Width > 0)
+		f.SetDraw(f.Width > 0)
+	}
+	set(p.header, p.headerBackground, header)
+	set(p.footer, p.footerBackground, footer)
 }
 
 func (p *playerListUI) free() {
@@ -100,6 +138,13 @@ func (p *playerListUI) render(delta float64) {
 	for _, e := range p.entries {
 		e.set(false)
 	}
+	top := 16.0
+	if p.headerBackground.ShouldDraw() {
+		top += p.headerBackground.Height() + 2
+	}
+	for _, b := range p.background {
+		b.SetY(top)
+	}
 	offset := 0
 	count := 0
 	bTab := 0
@@ -125,7 +170,7 @@ func (p *playerListUI) render(delta float64) {
 		background := p.background[bTab]
 		background.SetDraw(true)
 		if offset >= len(p.entries) {
-			text := ui.NewText("", 24, 0, 255, 255, 255).
+			text := ui.NewFormatted(format.Wrap(&format.TextComponent{}), 24, 0).
 				Attach(ui.Top, ui.Left)
 			p.scene.AddDrawable(text)
 			icon := ui.NewImage(pl.skin, 0, 0, 16, 16, 8/64.0, 8/64.0, 8/64.0, 8/64.0, 255, 255, 255).
@@ -151,30 +196,39 @@ func (p *playerListUI) render(delta float64) {
 			})
 		}
 		e := p.entries[offset]
+		e.uuid = pl.uuid
 		e.set(true)
 		offset++
 		e.text.SetY(1 + 18*float64(count))
-		e.text.Update(pl.name)
+		e.text.Update(pl.listName())
 		e.icon.SetY(1 + 18*float64(count))
 		e.icon.SetTexture(pl.skin)
 		e.iconHat.SetY(1 + 18*float64(count))
 		e.iconHat.SetTexture(pl.skin)
+		// Spectators are faded out
+		alpha := 255
+		if pl.gameMode == gmSpecator {
+			alpha = 128
+		}
+		e.icon.SetA(alpha)
+		e.iconHat.SetA(alpha)
 
 		e.ping.SetY(1 + 18*float64(count))
+		// Same thresholds as vanilla
 		y := 0.0
 		switch {
-		case pl.ping <= 75:
+		case pl.ping < 0:
+			y = 56 / 256.0
+		case pl.ping < 150:
 			y = 16 / 256.0
-		case pl.ping <= 150:
+		case pl.ping < 300:
 			y = 24 / 256.0
-		case pl.ping <= 225:
+		case pl.ping < 600:
 			y = 32 / 256.0
-		case pl.ping <= 350:
+		case pl.ping < 1000:
 			y = 40 / 256.0
-		case pl.ping < 999:
-			y = 48 / 256.0
 		default:
-			y = 56 / 256.0
+			y = 48 / 256.0
 		}
 		e.ping.SetTextureY(y)
 		count++
@@ -208,6 +262,33 @@ func (p *playerListUI) render(delta float64) {
 		p.background[1].SetX(-p.background[1].Width() / 2)
 		p.background[2].SetX(p.background[2].Width() / 2)
 	}
+
+	// The footer sits under the longest column
+	if p.footerBackground.ShouldDraw() {
+		bottom := 0.0
+		for _, b := range p.background {
+			if b.ShouldDraw() && b.Height() > bottom {
+				bottom = b.Height()
+			}
+		}
+		p.footerBackground.SetY(top + bottom + 2)
+	}
+}
+
+// spectate teleports the player to the player under the passed
+// position, if any. Only works in spectator mode.
+func (p *playerListUI) spectate(x, y float64, w, h int) {
+	for _, e := range p.entries {
+		if !e.text.ShouldDraw() {
+			continue
+		}
+		_, _, okText := ui.Intersects(e.text, x, y, w, h)
+		_, _, okIcon := ui.Intersects(e.icon, x, y, w, h)
+		if okText || okIcon {
+			Client.network.Write(&protocol.SpectateTeleport{Target: e.uuid})
+			return
+		}
+	}
 }
 
 func (p *playerListUI) players() (out []*playerInfo) {
@@ -218,10 +299,34 @@ func (p *playerListUI) players() (out []*playerInfo) {
 	return
 }
 
+// listName returns the name to display in the player list,
+// using the name provided by the server if set.
+func (pl *playerInfo) listName() format.AnyComponent {
+	var name format.AnyComponent
+	if pl.displayName.Value != nil {
+		name = pl.displayName
+	} else {
+		name = format.Wrap(&format.TextComponent{Text: pl.name})
+	}
+	if pl.gameMode != gmSpecator {
+		return name
+	}
+	return format.Wrap(&format.TextComponent{
+		Component: format.Component{
+			Color: format.Gray,
+			Extra: []format.AnyComponent{name},
+		},
+	})
+}
+
 type sortedPlayerList []*playerInfo
 
 func (s sortedPlayerList) Len() int { return len(s) }
 func (s sortedPlayerList) Less(a, b int) bool {
+	// Spectators are listed after everyone else
+	if sa, sb := s[a].gameMode == gmSpecator, s[b].gameMode == gmSpecator; sa != sb {
+		return sb
+	}
 	if s[a].name < s[b].name {
 		return true
 	}
@@ -31,6 +31,8 @@ type chunkShader struct {
 	Texture           gl.Uniform   `gl:"textures"`
 	LightLevel        gl.Uniform   `gl:"lightLevel"`
 	SkyOffset         gl.Uniform   `gl:"skyOffset"`
+	FogColor          gl.Uniform   `gl:"fogColor"`
+	FogDistance       gl.Uniform   `gl:"fogDistance"`
 }
 
 func init() {
@@ -52,13 +54,16 @@ out vec4 vTextureInfo;
 out vec2 vTextureOffset;
 out float vAtlas;
 out vec3 vLighting;
+out float vDistance;
 
 #include get_light
 
 void main() {
 	vec3 pos = vec3(aPosition.x, -aPosition.y, aPosition.z);
 	vec3 o = vec3(offset.x, -offset.y / 4096.0, offset.z);
-	gl_Position = perspectiveMatrix * cameraMatrix * vec4(pos + o * 16.0, 1.0);
+	vec4 viewPos = cameraMatrix * vec4(pos + o * 16.0, 1.0);
+	gl_Position = perspectiveMatrix * viewPos;
+	vDistance = length(viewPos.xyz);
 
 	vColor = aColor;
 	vTextureInfo = aTextureInfo;
@@ -70,12 +75,15 @@ void main() {
 `)
 	glsl.Register("chunk_frag", `
 uniform sampler2DArray textures;
+uniform vec3 fogColor;
+uniform vec2 fogDistance;
 
 in vec3 vColor;
 in vec4 vTextureInfo;
 in vec2 vTextureOffset;
 in float vAtlas;
 in vec3 vLighting;
+in float vDistance;
 
 #ifndef alpha
 out vec4 fragColor;
@@ -93,6 +101,8 @@ void main() {
 	#endif
 	col *= vec4(vColor, 1.0);
 	col.rgb *= vLighting;
+	float fog = clamp((vDistance - fogDistance.x) / (fogDistance.y - fogDistance.x), 0.0, 1.0);
+	col.rgb = mix(col.rgb, fogColor, fog);
 
 	#ifndef alpha
 	fragColor = col;
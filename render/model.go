@@ -134,6 +134,11 @@ func (m *Model) data() {
 	verts := m.Verts
 	m.array.Bind()
 	m.count = (len(verts) / 4) * 6
+	// Models with a single part may change their size
+	// when refreshed
+	if len(m.counts) == 1 {
+		m.counts[0] = int32(m.count)
+	}
 	if modelState.maxIndex < m.count {
 		var data []byte
 		data, modelState.indexType = genElementBuffer(m.count)
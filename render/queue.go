@@ -14,25 +14,43 @@
 
 package render
 
-const (
-	maxQueueSize = 1 << 12
-	queueMask    = maxQueueSize - 1
-)
-
+// renderQueue is a ring buffer of render requests that grows
+// as needed.
 type renderQueue struct {
-	queue        [maxQueueSize]renderRequest
+	queue        []renderRequest
 	startPointer int
 	endPointer   int
 }
 
 func (rq *renderQueue) Append(r renderRequest) {
+	if len(rq.queue) == 0 || (rq.endPointer+1)&(len(rq.queue)-1) == rq.startPointer {
+		rq.grow()
+	}
 	rq.queue[rq.endPointer] = r
-	rq.endPointer = (rq.endPointer + 1) & queueMask
+	rq.endPointer = (rq.endPointer + 1) & (len(rq.queue) - 1)
+}
+
+// grow doubles the size of the queue keeping the requests in
+// order.
+func (rq *renderQueue) grow() {
+	size := len(rq.queue) * 2
+	if size == 0 {
+		size = 1 << 12
+	}
+	queue := make([]renderRequest, size)
+	n := 0
+	for !rq.Empty() {
+		queue[n] = rq.Take()
+		n++
+	}
+	rq.queue = queue
+	rq.startPointer = 0
+	rq.endPointer = n
 }
 
 func (rq *renderQueue) Take() renderRequest {
 	val := rq.queue[rq.startPointer]
-	rq.startPointer = (rq.startPointer + 1) & queueMask
+	rq.startPointer = (rq.startPointer + 1) & (len(rq.queue) - 1)
 	return val
 }
 
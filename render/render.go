@@ -60,12 +60,36 @@ debugging.
 		122.0 / 255.0, 165.0 / 255.0, 247.0 / 255.0,
 	}
 
+	// ViewDistance is read by the client for loading chunks as
+	// well as here for drawing them
+	ViewDistance = console.NewIntVar("r_view_distance", 8, console.Mutable, console.Serializable).Doc(`
+r_view_distance controls the distance in chunks around the camera
+that the world is drawn and kept loaded. The server may send fewer
+chunks than this. The minimum is 2.
+`).Callback(func() { lastWidth = -1; lastHeight = -1 })
+
 	slidyChunks = console.NewBoolVar("r_slidy_chunks", false, console.Mutable, console.Serializable).Doc(`
 r_slidy_chunks makes chunks slide into view instead of just
 popping in.
 `)
 )
 
+// ViewDistanceChunks returns the view distance in chunks.
+func ViewDistanceChunks() int {
+	if d := ViewDistance.Value(); d > 2 {
+		return d
+	}
+	return 2
+}
+
+// farPlane returns the distance to the far plane of the
+// perspective matrix. It reaches the corners of the view distance
+// and the height of the world.
+func farPlane() float32 {
+	d := float64(ViewDistanceChunks()+1) * 16 * math.Sqrt2
+	return float32(math.Sqrt(d*d + 256*256))
+}
+
 // Start starts the renderer
 func Start() {
 	if os.Getenv("STEVEN_DEBUG") == "true" {
@@ -132,14 +156,14 @@ sync:
 			(math.Pi/180)*float32(lastFOV),
 			float32(width)/float32(height),
 			0.1,
-			500.0,
+			farPlane(),
 		)
 		gl.Viewport(0, 0, width, height)
 		frustum.SetPerspective(
 			(math.Pi/180)*float32(lastFOV),
 			float32(width)/float32(height),
 			0.1,
-			500.0,
+			farPlane(),
 		)
 		initTrans()
 	}
@@ -174,11 +198,17 @@ sync:
 		mgl32.Vec3{0, -1, 0},
 	)
 
+	// Fade the edge of the world into the sky
+	fogEnd := float32(ViewDistanceChunks() * 16)
+	fogStart := fogEnd * 0.75
+
 	shaderChunk.PerspectiveMatrix.Matrix4(&perspectiveMatrix)
 	shaderChunk.CameraMatrix.Matrix4(&cameraMatrix)
 	shaderChunk.Texture.Int(0)
 	shaderChunk.LightLevel.Float(LightLevel)
 	shaderChunk.SkyOffset.Float(SkyOffset)
+	shaderChunk.FogColor.Float3(ClearColour.R, ClearColour.G, ClearColour.B)
+	shaderChunk.FogDistance.Float2(fogStart, fogEnd)
 
 	chunkPos := position{
 		X: int(Camera.X) >> 4,
@@ -204,6 +234,8 @@ sync:
 	shaderChunkT.Texture.Int(0)
 	shaderChunkT.LightLevel.Float(LightLevel)
 	shaderChunkT.SkyOffset.Float(SkyOffset)
+	shaderChunkT.FogColor.Float3(ClearColour.R, ClearColour.G, ClearColour.B)
+	shaderChunkT.FogDistance.Float2(fogStart, fogEnd)
 
 	// Copy the depth buffer
 	mainFramebuffer.BindRead()
@@ -274,6 +306,8 @@ func renderBuffer(ch *ChunkBuffer, po position, fr direction.Type, delta float64
 		return
 	}
 	rQueue.Append(renderRequest{ch, po, fr})
+	cameraChunk := position{X: int(math.Floor(Camera.X)) >> 4, Z: int(math.Floor(Camera.Z)) >> 4}
+	maxDist := (ViewDistanceChunks() + 1) * (ViewDistanceChunks() + 1)
 
 	slidy := slidyChunks.Value()
 
@@ -283,6 +317,9 @@ func renderBuffer(ch *ChunkBuffer, po position, fr direction.Type, delta float64
 			continue
 		}
 		req.chunk.renderedOn = frameID
+		if dx, dz := req.pos.X-cameraChunk.X, req.pos.Z-cameraChunk.Z; dx*dx+dz*dz > maxDist {
+			continue
+		}
 
 		aabb := vmath.NewAABB(
 			-float32((req.pos.X<<4)+16), -float32((req.pos.Y<<4)+16), float32((req.pos.Z<<4)),
@@ -36,6 +36,10 @@ var (
 	loadedTextures   []*loadedTexture
 	textureLock      sync.RWMutex
 	animatedTextures []*animatedTexture
+	// The number of frames of textures with multiple frames but
+	// no animation info. The frame displayed is picked by the
+	// game (e.g. compasses)
+	frameTextures = map[string]int{}
 )
 
 const (
@@ -230,6 +234,7 @@ func LoadTextures() {
 	}
 	freeTextures = nil
 	animatedTextures = nil
+	frameTextures = map[string]int{}
 	textures = nil
 	pix := []byte{
 		0, 0, 0, 255,
@@ -304,13 +309,15 @@ func loadTexFile(st *loadedTexture) {
 	img := ii.(draw.Image)
 	width, height := img.Bounds().Dx(), img.Bounds().Dy()
 	var ani *animatedTexture
+	frameCount := 1
 	if (strings.HasPrefix(file, "textures/blocks") || strings.HasPrefix(file, "textures/items")) &&
 		width != height {
 		height = width
 		old := img
 		img = image.NewNRGBA(image.Rect(0, 0, width, width))
 		draw.Draw(img, img.Bounds(), old, image.ZP, draw.Over)
-		ani = loadAnimation(file, old.Bounds().Dy()/old.Bounds().Dx())
+		frames := old.Bounds().Dy() / old.Bounds().Dx()
+		ani = loadAnimation(file, frames)
 		if ani != nil {
 			ani.Image = old
 			ani.Buffer = imgToBytes(old)
@@ -318,6 +325,11 @@ func loadTexFile(st *loadedTexture) {
 		} else {
 			img = old
 			width, height = img.Bounds().Dx(), img.Bounds().Dy()
+			if strings.HasPrefix(file, "textures/items") {
+				// The whole strip is kept so that each item
+				// can display a different frame
+				frameCount = frames
+			}
 		}
 	}
 	pix := imgToBytes(img)
@@ -325,6 +337,9 @@ func loadTexFile(st *loadedTexture) {
 	if st.Plugin != "minecraft" {
 		name = st.Plugin + ":" + name
 	}
+	if frameCount > 1 {
+		frameTextures[name] = frameCount
+	}
 	info := addTexture(pix, width, height)
 	if t, ok := textureMap[name]; ok {
 		t.atlas = info.atlas
@@ -410,6 +425,19 @@ type textureFrame struct {
 	Time  int
 }
 
+// FrameCount returns the number of frames the named texture
+// has. The frames are stacked vertically in the texture and
+// which is displayed is left to the user of the texture.
+// Textures without any frames return 1.
+func FrameCount(name string) int {
+	textureLock.RLock()
+	defer textureLock.RUnlock()
+	if frames, ok := frameTextures[name]; ok {
+		return frames
+	}
+	return 1
+}
+
 func tickAnimatedTextures(delta float64) {
 	delta /= 3 // default is 60 a second, minecraft is 20
 	for _, ani := range animatedTextures {
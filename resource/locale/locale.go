@@ -23,21 +23,46 @@ import (
 	"github.com/thinkofdeath/steven/resource"
 )
 
+// Default is the locale used for strings missing from the
+// current locale.
+const Default = "en_US"
+
 var (
-	values = map[string]string{}
-	lock   sync.RWMutex
+	values  = map[string]string{}
+	current = Default
+	lock    sync.RWMutex
 )
 
 func init() {
-	LoadLocale("en_US")
+	LoadLocale(Default)
 }
 
-// Clear clears loaded strings and reloads the default locale.
+// Clear clears loaded strings and reloads the default and current
+// locales.
 func Clear() {
 	lock.Lock()
 	values = map[string]string{}
+	name := current
 	lock.Unlock()
-	LoadLocale("en_US")
+	LoadLocale(Default)
+	if name != Default {
+		LoadLocale(name)
+	}
+}
+
+// SetCurrent changes the locale strings are loaded from.
+func SetCurrent(name string) {
+	lock.Lock()
+	current = name
+	lock.Unlock()
+	Clear()
+}
+
+// Current returns the name of the locale strings are loaded from.
+func Current() string {
+	lock.RLock()
+	defer lock.RUnlock()
+	return current
 }
 
 // LoadLocale loads the named locale if it exists.
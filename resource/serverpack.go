@@ -0,0 +1,118 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrHashMismatch is returned when a downloaded server resource pack
+// doesn't match the hash the server provided.
+var ErrHashMismatch = errors.New("resource pack hash mismatch")
+
+var validHash = regexp.MustCompile(`^[a-f0-9]{40}$`)
+
+// Downloader fetches the file at the passed url.
+type Downloader func(url string) (io.ReadCloser, error)
+
+// HTTPDownloader is the default Downloader which fetches files
+// over http(s).
+func HTTPDownloader(url string) (io.ReadCloser, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// FetchServerPack returns the path to the resource pack at the passed
+// url, downloading it into the cache directory if needed. Packs are
+// stored using their hash as their name so that they only have to be
+// downloaded once. If the server provided a valid SHA-1 hash the
+// pack is checked against it, otherwise the pack is named after the
+// url and isn't verified.
+func FetchServerPack(dir, url, hash string, dl Downloader) (string, error) {
+	hash = strings.ToLower(hash)
+	verify := validHash.MatchString(hash)
+	name := hash
+	if !verify {
+		h := sha1.Sum([]byte(url))
+		name = hex.EncodeToString(h[:])
+	}
+	path := filepath.Join(dir, name+".zip")
+
+	if verify {
+		if h, err := hashFile(path); err == nil && h == hash {
+			return path, nil
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+	r, err := dl(url)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	h := sha1.New()
+	_, err = io.Copy(f, io.TeeReader(r, h))
+	f.Close()
+	if err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	if verify && hex.EncodeToString(h.Sum(nil)) != hash {
+		os.Remove(tmp)
+		return "", ErrHashMismatch
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
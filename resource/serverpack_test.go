@@ -0,0 +1,117 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testPack = []byte("not really a zip but good enough")
+
+func testServer(requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Path != "/pack.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(testPack)
+	}))
+}
+
+func testHash() string {
+	h := sha1.Sum(testPack)
+	return hex.EncodeToString(h[:])
+}
+
+func TestFetchServerPack(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serverpack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	requests := 0
+	srv := testServer(&requests)
+	defer srv.Close()
+
+	hash := testHash()
+	path, err := FetchServerPack(dir, srv.URL+"/pack.zip", hash, HTTPDownloader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, hash+".zip") {
+		t.Errorf("unexpected path %q", path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(testPack) {
+		t.Errorf("pack contents don't match")
+	}
+
+	// Second fetch should come from the cache
+	if _, err := FetchServerPack(dir, srv.URL+"/pack.zip", hash, HTTPDownloader); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestFetchServerPackMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serverpack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	requests := 0
+	srv := testServer(&requests)
+	defer srv.Close()
+
+	hash := "0000000000000000000000000000000000000000"
+	_, err = FetchServerPack(dir, srv.URL+"/pack.zip", hash, HTTPDownloader)
+	if err != ErrHashMismatch {
+		t.Fatalf("expected hash mismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, hash+".zip")); !os.IsNotExist(err) {
+		t.Errorf("mismatched pack was kept")
+	}
+}
+
+func TestFetchServerPackNoHash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serverpack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	requests := 0
+	srv := testServer(&requests)
+	defer srv.Close()
+
+	if _, err := FetchServerPack(dir, srv.URL+"/pack.zip", "", HTTPDownloader); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FetchServerPack(dir, srv.URL+"/missing.zip", "", HTTPDownloader); err == nil {
+		t.Errorf("expected an error for a missing pack")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/thinkofdeath/steven/console"
+	"github.com/thinkofdeath/steven/protocol"
 	"github.com/thinkofdeath/steven/render"
 	"github.com/thinkofdeath/steven/resource"
 	"github.com/thinkofdeath/steven/resource/locale"
@@ -31,6 +32,21 @@ cl_resource_packs is a comma seperated list of resource packs
 that are currently enabled.
 `)
 
+var clientLocale = console.NewStringVar("cl_locale", locale.Default, console.Mutable, console.Serializable).Doc(`
+cl_locale is the language used for text and sent to the
+server, e.g. en_US or de_DE.
+`)
+
+func init() {
+	clientLocale.Callback(func() {
+		locale.SetCurrent(clientLocale.Value())
+		// Only send the settings whilst in a world
+		if Client != nil && ready {
+			Client.sendSettings()
+		}
+	})
+}
+
 func initResources() {
 	var pBar *progressBar
 	resource.Init(func(progress float64, done bool) {
@@ -90,13 +106,92 @@ func RemovePack(path string) {
 	reloadResources()
 }
 
+// The directory server resource packs are cached in
+const serverPackDir = "./server-resource-packs"
+
+// serverPackDownloader is used to fetch server resource packs.
+// Replaceable for testing.
+var serverPackDownloader resource.Downloader = resource.HTTPDownloader
+
+// The path of the currently loaded server resource pack, if any
+var currentServerPack string
+
+// serverPackSession is changed every time the client leaves a
+// server so that downloads started for that server are dropped
+// when they finish.
+var serverPackSession int
+
+type serverPackStatus int
+
+const (
+	serverPackLoaded serverPackStatus = iota
+	serverPackDeclined
+	serverPackFailed
+	serverPackAccepted
+)
+
+func sendServerPackStatus(hash string, status serverPackStatus) {
+	Client.network.Write(&protocol.ResourcePackStatus{
+		Hash:   hash,
+		Result: protocol.VarInt(status),
+	})
+}
+
+// acceptServerPack downloads (if not already cached) and loads the
+// passed server resource pack above all the others.
+func acceptServerPack(url, hash string) {
+	sendServerPackStatus(hash, serverPackAccepted)
+	console.Text("Downloading server resource pack %s", url)
+	session := serverPackSession
+	go func() {
+		path, err := resource.FetchServerPack(serverPackDir, url, hash, serverPackDownloader)
+		syncChan <- func() {
+			// The client left the server that asked for the
+			// pack whilst it was downloading
+			if session != serverPackSession {
+				return
+			}
+			if err != nil {
+				console.Text("Failed to download server resource pack: %s", err)
+				sendServerPackStatus(hash, serverPackFailed)
+				return
+			}
+			if currentServerPack != "" {
+				resource.RemovePack(currentServerPack)
+				currentServerPack = ""
+			}
+			if err := resource.LoadZip(path); err != nil {
+				console.Text("Failed to load server resource pack: %s", err)
+				sendServerPackStatus(hash, serverPackFailed)
+				return
+			}
+			currentServerPack = path
+			reloadResources()
+			sendServerPackStatus(hash, serverPackLoaded)
+		}
+	}()
+}
+
+// unloadServerPack removes the server's resource pack (if any)
+// once the client leaves the server.
+func unloadServerPack() {
+	serverPackSession++
+	if currentServerPack == "" {
+		return
+	}
+	console.Text("Removing server resource pack")
+	resource.RemovePack(currentServerPack)
+	currentServerPack = ""
+	reloadResources()
+}
+
 func reloadResources() {
 	console.Text("Bringing everything to a stop")
 	for freeBuilders < maxBuilders {
 		select {
 		case pos := <-completeBuilders:
 			freeBuilders++
-			if c := chunkMap[chunkPosition{pos.X, pos.Z}]; c != nil {
+			if c := chunkMap.chunks[chunkPosition{pos.X, pos.Z}]; c != nil {
 				if s := c.Sections[pos.Y]; s != nil {
 					s.building = false
 				}
@@ -114,7 +209,7 @@ func reloadResources() {
 	console.Text("Reloading blocks")
 	reinitBlocks()
 	console.Text("Marking chunks for rebuild")
-	for _, c := range chunkMap {
+	for _, c := range chunkMap.chunks {
 		for _, s := range c.Sections {
 			if s != nil {
 				s.dirty = true
@@ -0,0 +1,243 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/thinkofdeath/steven/resource/locale"
+)
+
+type statCategory int
+
+const (
+	statGeneral statCategory = iota
+	statBlocks
+	statItems
+	statMobs
+)
+
+// statColumn is a value displayed for each entry in a
+// category. The statistic is found by appending the entry's
+// name to the prefix.
+type statColumn struct {
+	title  string
+	prefix string
+}
+
+var statCategories = [...]struct {
+	title   string
+	columns []statColumn
+}{
+	statGeneral: {title: "General"},
+	statBlocks: {title: "Blocks", columns: []statColumn{
+		{"Crafted", "stat.craftItem."},
+		{"Used", "stat.useItem."},
+		{"Mined", "stat.mineBlock."},
+	}},
+	statItems: {title: "Items", columns: []statColumn{
+		{"Depleted", "stat.breakItem."},
+		{"Crafted", "stat.craftItem."},
+		{"Used", "stat.useItem."},
+	}},
+	statMobs: {title: "Mobs", columns: []statColumn{
+		{"Killed", "stat.killEntity."},
+		{"Killed by", "stat.entityKilledBy."},
+	}},
+}
+
+// statRow is a single line in the statistics screen.
+type statRow struct {
+	name   string
+	values []string
+}
+
+type sortedStatRows []statRow
+
+func (s sortedStatRows) Len() int           { return len(s) }
+func (s sortedStatRows) Less(a, b int) bool { return s[a].name < s[b].name }
+func (s sortedStatRows) Swap(a, b int)      { s[a], s[b] = s[b], s[a] }
+
+// statisticRows returns the rows to display for the category
+// sorted by their localized name.
+func statisticRows(cat statCategory, stats map[string]int) []statRow {
+	var rows []statRow
+	if cat == statGeneral {
+		for name, val := range stats {
+			if !strings.HasPrefix(name, "stat.") || strings.Count(name, ".") != 1 {
+				continue
+			}
+			rows = append(rows, statRow{
+				name:   locale.GetRaw(name),
+				values: []string{formatStatistic(name, val)},
+			})
+		}
+		sort.Sort(sortedStatRows(rows))
+		return rows
+	}
+
+	columns := statCategories[cat].columns
+	// Find every entry that has at least one statistic
+	// in this category
+	entries := map[string]string{}
+	for name := range stats {
+		for _, col := range columns {
+			if !strings.HasPrefix(name, col.prefix) {
+				continue
+			}
+			key := name[len(col.prefix):]
+			if _, ok := entries[key]; ok {
+				break
+			}
+			if label, ok := statEntryName(cat, key); ok {
+				entries[key] = label
+			}
+			break
+		}
+	}
+	for key, label := range entries {
+		row := statRow{name: label}
+		for _, col := range columns {
+			val := "-"
+			if v, ok := stats[col.prefix+key]; ok {
+				val = formatStatistic(col.prefix+key, v)
+			}
+			row.values = append(row.values, val)
+		}
+		rows = append(rows, row)
+	}
+	sort.Sort(sortedStatRows(rows))
+	return rows
+}
+
+// statEntryName returns the localized name of the block, item
+// or mob the statistic is for. Returns false if the entry doesn't
+// belong to the category.
+func statEntryName(cat statCategory, key string) (string, bool) {
+	if cat == statMobs {
+		return locale.GetRaw(fmt.Sprintf("entity.%s.name", key)), true
+	}
+	ty := statItem(key)
+	if ty == nil {
+		// Unknown items are listed under their raw name
+		return key, cat == statItems
+	}
+	_, isBlock := ty.(*blockItem)
+	if isBlock != (cat == statBlocks) {
+		return "", false
+	}
+	return locale.GetRaw(ty.NameLocaleKey()), true
+}
+
+// statItem returns the item type for the passed statistic key.
+// Older servers use the numeric id whilst newer ones use the
+// item's name (e.g. minecraft.stone).
+func statItem(key string) ItemType {
+	if id, err := strconv.Atoi(key); err == nil {
+		return ItemById(id)
+	}
+	name := strings.TrimPrefix(key, "minecraft.")
+	for _, bs := range blockSetsByID {
+		if bs == nil {
+			continue
+		}
+		if b := bs.Base; b.Plugin() == "minecraft" && b.Name() == name {
+			return ItemOfBlock(b)
+		}
+	}
+	for _, f := range itemsByID {
+		if ty := f(); ty.Name() == name {
+			return ty
+		}
+	}
+	return nil
+}
+
+// formatStatistic formats the value of the named statistic
+// in the same way vanilla does.
+func formatStatistic(name string, val int) string {
+	switch name {
+	case "stat.playOneMinute", "stat.timeSinceDeath", "stat.sneakTime":
+		return formatStatTime(val)
+	case "stat.damageDealt", "stat.damageTaken":
+		return fmt.Sprintf("%.2f", float64(val)*0.1)
+	}
+	if strings.HasSuffix(name, "OneCm") {
+		return formatStatDistance(val)
+	}
+	return formatStatInteger(val)
+}
+
+// formatStatTime formats a number of ticks using the largest
+// unit that is over a half.
+func formatStatTime(ticks int) string {
+	seconds := float64(ticks) / 20
+	minutes := seconds / 60
+	hours := minutes / 60
+	days := hours / 24
+	years := days / 365
+	switch {
+	case years > 0.5:
+		return fmt.Sprintf("%.2f y", years)
+	case days > 0.5:
+		return fmt.Sprintf("%.2f d", days)
+	case hours > 0.5:
+		return fmt.Sprintf("%.2f h", hours)
+	case minutes > 0.5:
+		return fmt.Sprintf("%.2f m", minutes)
+	}
+	s := strconv.FormatFloat(seconds, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s + " s"
+}
+
+// formatStatDistance formats a distance in centimeters.
+func formatStatDistance(cm int) string {
+	meters := float64(cm) / 100
+	km := meters / 1000
+	switch {
+	case km > 0.5:
+		return fmt.Sprintf("%.2f km", km)
+	case meters > 0.5:
+		return fmt.Sprintf("%.2f m", meters)
+	}
+	return fmt.Sprintf("%d cm", cm)
+}
+
+// formatStatInteger formats the number with commas separating
+// each group of thousands.
+func formatStatInteger(val int) string {
+	s := strconv.Itoa(val)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	var out []byte
+	for i := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, s[i])
+	}
+	if neg {
+		return "-" + string(out)
+	}
+	return string(out)
+}
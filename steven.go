@@ -103,6 +103,7 @@ func getProfile() mojang.Profile {
 }
 
 func connect(server string) {
+	closeLocalWorld()
 	setScreen(nil)
 	connected = true
 	initClient()
@@ -145,8 +146,10 @@ var (
 	freeBuilders     = maxBuilders
 	completeBuilders = make(chan buildPos, maxBuilders)
 	syncChan         = make(chan func(), 200)
-	ticker           = time.NewTicker(time.Second / 20)
 	lastFrame        = time.Now()
+	// tickTime is the time since the last tick that hasn't
+	// been ticked yet
+	tickTime time.Duration
 )
 
 func handleErrors() {
@@ -160,6 +163,7 @@ handle:
 			connected = false
 
 			Client.network.Close()
+			closeLocalWorld()
 			console.Text("Disconnected: %s", err)
 			// Reset the ready state to stop packets from being
 			// sent.
@@ -174,6 +178,7 @@ handle:
 				Client.entityAdded = false
 				Client.entities.container.RemoveEntity(Client.entity)
 			}
+			unloadServerPack()
 
 			setScreen(newServerList())
 		default:
@@ -194,7 +199,7 @@ handle:
 			defaultHandler.Handle(packet)
 		case pos := <-completeBuilders:
 			freeBuilders++
-			if c := chunkMap[chunkPosition{pos.X, pos.Z}]; c != nil {
+			if c := chunkMap.chunks[chunkPosition{pos.X, pos.Z}]; c != nil {
 				if s := c.Sections[pos.Y]; s != nil {
 					s.building = false
 				}
@@ -215,12 +220,17 @@ handle:
 
 	if ready && Client != nil {
 		Client.renderTick(delta)
-		select {
-		case <-ticker.C:
+		// Run as many ticks as time has passed for so slow
+		// frames don't slow down the game
+		tickTime += diff
+		if tickTime > maxCatchUpTicks*physicsTickTime {
+			tickTime = maxCatchUpTicks * physicsTickTime
+		}
+		for ; tickTime >= physicsTickTime; tickTime -= physicsTickTime {
 			tick()
-		default:
 		}
 	} else {
+		tickTime = 0
 		render.Camera.Yaw += 0.005 * delta
 		if render.Camera.Yaw > math.Pi*2 {
 			render.Camera.Yaw = 0
@@ -234,7 +244,6 @@ handle:
 	tickClouds(delta)
 
 	render.Draw(width, height, delta)
-	chunks := sortedChunks()
 
 	// Search for 'dirty' chunk sections and start building
 	// them if we have any builders free. To prevent race conditions
@@ -243,29 +252,31 @@ handle:
 	// without either losing the change or having two builds
 	// for the same section going on at once (where the second
 	// could finish quicker causing the old version to be
-	// displayed.
-dirtyClean:
-	for _, c := range chunks {
-		for _, s := range c.Sections {
-			if s == nil {
-				continue
-			}
-			if freeBuilders <= 0 {
-				break dirtyClean
-			}
-			if s.dirty && !s.building && s.Buffer.Rendered {
-				freeBuilders--
-				s.dirty = false
-				s.building = true
-				s.build(completeBuilders)
-			}
+	// displayed. The closest sections are built first.
+	if freeBuilders <= 0 {
+		return
+	}
+	for _, s := range dirtySections() {
+		if freeBuilders <= 0 {
+			break
 		}
+		freeBuilders--
+		s.dirty = false
+		s.building = true
+		s.build(completeBuilders)
 	}
 }
 
 // tick is called 20 times a second (bar any preformance issues).
 // Minecraft is built around this fact so we have to follow it
 // as well.
+// maxCatchUpTicks is the most ticks run in a single frame
+// after a long pause, like vanilla.
+const maxCatchUpTicks = 10
+
 func tick() {
 	Client.tick()
+	if localWorld != nil {
+		localWorld.tick()
+	}
 }
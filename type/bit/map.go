@@ -34,7 +34,7 @@ func NewMapFromRaw(bits []uint64, size int) *Map {
 	return &Map{
 		BitSize: size,
 		bits:    bits,
-		Length:  (len(bits)*64 + 63) / size,
+		Length:  len(bits) * 64 / size,
 	}
 }
 
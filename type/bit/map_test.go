@@ -42,3 +42,20 @@ func TestMapOdd(t *testing.T) {
 		}
 	}
 }
+
+func TestMapFromRaw(t *testing.T) {
+	m := NewMap(4096, 5)
+	for i := 0; i < 4096; i++ {
+		m.Set(i, i&0x1F)
+	}
+	raw := NewMapFromRaw(m.bits, 5)
+	if raw.Length != 4096 {
+		t.Fatalf("Length wanted 4096 and got %d", raw.Length)
+	}
+	resized := raw.ResizeBits(10)
+	for i := 0; i < 4096; i++ {
+		if resized.Get(i) != i&0x1F {
+			t.Fatalf("Index(%d) wanted %d and got %d", i, i&0x1F, resized.Get(i))
+		}
+	}
+}
@@ -37,21 +37,33 @@ func newGameMenu() screen {
 	gm.background.SetA(160)
 	gm.scene.AddDrawable(gm.background.Attach(ui.Top, ui.Left))
 
-	disconnect, txt := newButtonText("Disconnect", 0, 50, 400, 40)
+	disconnect, txt := newButtonText("Disconnect", 0, 75, 400, 40)
 	gm.scene.AddDrawable(disconnect.Attach(ui.Center, ui.Middle))
 	gm.scene.AddDrawable(txt)
 	disconnect.AddClick(func() { Client.network.SignalClose(errManualDisconnect) })
 
-	rtg, txt := newButtonText("Return to game", 0, -50, 400, 40)
+	rtg, txt := newButtonText("Return to game", 0, -75, 400, 40)
 	gm.scene.AddDrawable(rtg.Attach(ui.Center, ui.Middle))
 	gm.scene.AddDrawable(txt)
 	rtg.AddClick(func() { setScreen(nil) })
 
-	option, txt := newButtonText("Options", 0, 0, 400, 40)
+	option, txt := newButtonText("Options", 0, -25, 400, 40)
 	gm.scene.AddDrawable(option.Attach(ui.Center, ui.Middle))
 	gm.scene.AddDrawable(txt)
 	option.AddClick(func() { setScreen(newOptionMenu(newGameMenu)) })
 
+	stats, txt := newButtonText("Statistics", 0, 25, 400, 40)
+	gm.scene.AddDrawable(stats.Attach(ui.Center, ui.Middle))
+	gm.scene.AddDrawable(txt)
+	stats.AddClick(func() { setScreen(newStatisticsMenu()) })
+
+	diff := Client.Difficulty.String()
+	if Client.HardCore {
+		diff = "Hardcore"
+	}
+	difficulty := ui.NewText("Difficulty: "+diff, 0, 125, 255, 255, 255).Attach(ui.Center, ui.Middle)
+	gm.scene.AddDrawable(difficulty)
+
 	uiFooter(gm.scene)
 	return gm
 }
@@ -15,17 +15,30 @@
 package steven
 
 import (
+	"fmt"
+
+	"github.com/thinkofdeath/steven/format"
 	"github.com/thinkofdeath/steven/protocol"
 	"github.com/thinkofdeath/steven/render"
 	"github.com/thinkofdeath/steven/ui"
 	"github.com/thinkofdeath/steven/ui/scene"
 )
 
+// The number of ticks (at 60 per a second) before the
+// buttons can be pressed. Stops accidental respawns
+// whilst still clicking.
+const respawnButtonDelay = 60
+
 type respawnScreen struct {
 	baseUI
 	scene *scene.Type
 
 	background *ui.Image
+	message    *ui.Formatted
+	score      *ui.Text
+
+	respawn, titleScreen *ui.Button
+	enableTimer          float64
 }
 
 func newRespawnScreen() *respawnScreen {
@@ -34,34 +47,76 @@ func newRespawnScreen() *respawnScreen {
 	}
 
 	rs.background = ui.NewImage(render.GetTexture("solid"), 0, 0, 854, 480, 0, 0, 1, 1, 0, 0, 0)
+	rs.background.SetR(80)
 	rs.background.SetA(160)
 	rs.scene.AddDrawable(rs.background.Attach(ui.Top, ui.Left))
 
-	rs.scene.AddDrawable(
-		ui.NewText("Respawn:", 0, -20, 255, 255, 255).Attach(ui.Center, ui.Middle),
-	)
+	title := "You died!"
+	if Client.HardCore {
+		title = "Game over!"
+	}
+	t := ui.NewText(title, 0, -120, 255, 255, 255).Attach(ui.Center, ui.Middle)
+	t.SetScaleX(2)
+	t.SetScaleY(2)
+	rs.scene.AddDrawable(t)
+
+	rs.message = ui.NewFormatted(format.Wrap(&format.TextComponent{}), 0, -70).Attach(ui.Center, ui.Middle)
+	rs.scene.AddDrawable(rs.message)
+	rs.setMessage(Client.deathMessage)
+
+	rs.score = ui.NewText("", 0, -40, 255, 255, 255).Attach(ui.Center, ui.Middle)
+	rs.scene.AddDrawable(rs.score)
+	rs.score.Update(fmt.Sprintf("Score: %d", Client.Score))
 
-	respawn, txt := newButtonText("Respawn", -205, 20, 400, 40)
+	respawn, txt := newButtonText("Respawn", 0, 20, 400, 40)
 	rs.scene.AddDrawable(respawn.Attach(ui.Center, ui.Middle))
 	rs.scene.AddDrawable(txt)
 	respawn.AddClick(func() {
+		if respawn.Disabled() {
+			return
+		}
 		setScreen(nil)
 		Client.network.Write(&protocol.ClientStatus{ActionID: 0})
 	})
+	respawn.SetDisabled(true)
+	rs.respawn = respawn
 
-	disconnect, txt := newButtonText("Disconnect", 205, 20, 400, 40)
-	rs.scene.AddDrawable(disconnect.Attach(ui.Center, ui.Middle))
+	titleScreen, txt := newButtonText("Title screen", 0, 70, 400, 40)
+	rs.scene.AddDrawable(titleScreen.Attach(ui.Center, ui.Middle))
 	rs.scene.AddDrawable(txt)
-	disconnect.AddClick(func() { Client.network.SignalClose(errManualDisconnect) })
+	titleScreen.AddClick(func() {
+		if titleScreen.Disabled() {
+			return
+		}
+		Client.network.SignalClose(errManualDisconnect)
+	})
+	titleScreen.SetDisabled(true)
+	rs.titleScreen = titleScreen
 
 	uiFooter(rs.scene)
 	return rs
 }
 
+// setMessage changes the death message displayed on the screen.
+func (rs *respawnScreen) setMessage(msg format.AnyComponent) {
+	if msg.Value == nil {
+		msg = format.Wrap(&format.TextComponent{})
+	}
+	rs.message.Update(msg)
+}
+
 func (rs *respawnScreen) tick(delta float64) {
 	width, height := window.GetFramebufferSize()
 	rs.background.SetWidth(float64(width) / ui.Scale)
 	rs.background.SetHeight(float64(height) / ui.Scale)
+
+	if rs.enableTimer < respawnButtonDelay {
+		rs.enableTimer += delta
+		if rs.enableTimer >= respawnButtonDelay {
+			rs.respawn.SetDisabled(false)
+			rs.titleScreen.SetDisabled(false)
+		}
+	}
 }
 
 func (rs *respawnScreen) remove() {
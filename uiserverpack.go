@@ -0,0 +1,74 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/ui"
+	"github.com/thinkofdeath/steven/ui/scene"
+)
+
+type serverPackPrompt struct {
+	baseUI
+	scene *scene.Type
+
+	background *ui.Image
+}
+
+func newServerPackPrompt(url, hash string) *serverPackPrompt {
+	sp := &serverPackPrompt{
+		scene: scene.New(true),
+	}
+
+	sp.background = ui.NewImage(render.GetTexture("solid"), 0, 0, 854, 480, 0, 0, 1, 1, 0, 0, 0)
+	sp.background.SetA(160)
+	sp.scene.AddDrawable(sp.background.Attach(ui.Top, ui.Left))
+
+	sp.scene.AddDrawable(
+		ui.NewText("The server recommends a custom resource pack.", 0, -40, 255, 255, 255).Attach(ui.Center, ui.Middle),
+	)
+	sp.scene.AddDrawable(
+		ui.NewText("Download and install it?", 0, -18, 255, 255, 255).Attach(ui.Center, ui.Middle),
+	)
+
+	yes, txt := newButtonText("Yes", -205, 30, 400, 40)
+	sp.scene.AddDrawable(yes.Attach(ui.Center, ui.Middle))
+	sp.scene.AddDrawable(txt)
+	yes.AddClick(func() {
+		setScreen(nil)
+		acceptServerPack(url, hash)
+	})
+
+	no, txt := newButtonText("No", 205, 30, 400, 40)
+	sp.scene.AddDrawable(no.Attach(ui.Center, ui.Middle))
+	sp.scene.AddDrawable(txt)
+	no.AddClick(func() {
+		setScreen(nil)
+		sendServerPackStatus(hash, serverPackDeclined)
+	})
+
+	uiFooter(sp.scene)
+	return sp
+}
+
+func (sp *serverPackPrompt) tick(delta float64) {
+	width, height := window.GetFramebufferSize()
+	sp.background.SetWidth(float64(width) / ui.Scale)
+	sp.background.SetHeight(float64(height) / ui.Scale)
+}
+
+func (sp *serverPackPrompt) remove() {
+	sp.scene.Hide()
+}
@@ -0,0 +1,195 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"unicode"
+
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/thinkofdeath/steven/format"
+	"github.com/thinkofdeath/steven/protocol"
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/ui"
+	"github.com/thinkofdeath/steven/ui/scene"
+)
+
+// The widest a single line on a sign can be. This matches
+// vanilla's limit of 90 pixels, doubled as our font is twice
+// the size.
+const signMaxLineWidth = 90 * 2
+
+type signEditor struct {
+	baseUI
+	scene *scene.Type
+
+	background *ui.Image
+	text       [4]*ui.Text
+
+	position Position
+	sign     SignComponent
+
+	lines      [4][]rune
+	line       int
+	cursorTick float64
+}
+
+func newSignEditor(pos Position) *signEditor {
+	se := &signEditor{
+		scene:    scene.New(true),
+		position: pos,
+	}
+
+	se.background = ui.NewImage(render.GetTexture("solid"), 0, 0, 854, 480, 0, 0, 1, 1, 0, 0, 0)
+	se.background.SetA(100)
+	se.scene.AddDrawable(se.background.Attach(ui.Top, ui.Left))
+
+	se.scene.AddDrawable(
+		ui.NewText("Edit sign message:", 0, -110, 255, 255, 255).Attach(ui.Center, ui.Middle),
+	)
+
+	for i := range se.text {
+		se.text[i] = ui.NewText("", 0, -60+22*float64(i), 255, 255, 255).Attach(ui.Center, ui.Middle)
+		se.scene.AddDrawable(se.text[i])
+	}
+
+	done, txt := newButtonText("Done", 0, 80, 400, 40)
+	se.scene.AddDrawable(done.Attach(ui.Center, ui.Middle))
+	se.scene.AddDrawable(txt)
+	done.AddClick(se.done)
+
+	// Start from the sign's current text (if any) so that
+	// editing an existing sign doesn't clear it.
+	if s, ok := chunkMap.BlockEntity(pos.X, pos.Y, pos.Z).(SignComponent); ok {
+		se.sign = s
+		for i, l := range s.Lines() {
+			if l.Value == nil {
+				continue
+			}
+			se.lines[i] = []rune(format.StripLegacy(l.String()))
+		}
+	}
+	se.updateText()
+
+	uiFooter(se.scene)
+	return se
+}
+
+func (se *signEditor) init() {
+	window.SetKeyCallback(se.handleKey)
+	window.SetCharCallback(se.handleChar)
+}
+
+func (se *signEditor) tick(delta float64) {
+	width, height := window.GetFramebufferSize()
+	se.background.SetWidth(float64(width) / ui.Scale)
+	se.background.SetHeight(float64(height) / ui.Scale)
+
+	se.cursorTick += delta
+	// Lazy way of preventing rounding errors buiding up over time
+	if se.cursorTick > 0xFFFFFF {
+		se.cursorTick = 0
+	}
+	se.updateText()
+}
+
+// updateText redraws the lines on the screen adding the cursor
+// to the line currently being edited.
+func (se *signEditor) updateText() {
+	for i, txt := range se.text {
+		line := string(se.lines[i])
+		if i == se.line && int(se.cursorTick/30)%2 == 0 {
+			line = "> " + line + "| <"
+		} else if i == se.line {
+			line = "> " + line + "  <"
+		}
+		if txt.Value() != line {
+			txt.Update(line)
+		}
+	}
+}
+
+// updateSign updates the sign in the world with the current text
+// so the changes can be previewed whilst editing.
+func (se *signEditor) updateSign() {
+	if se.sign == nil {
+		return
+	}
+	var lines [4]format.AnyComponent
+	for i, l := range se.lines {
+		lines[i] = format.Wrap(&format.TextComponent{Text: string(l)})
+	}
+	se.sign.Update(lines)
+}
+
+func (se *signEditor) handleKey(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	if action == glfw.Release {
+		if key == glfw.KeyEscape {
+			se.done()
+		}
+		return
+	}
+	switch key {
+	case glfw.KeyUp:
+		se.line = (se.line + 3) % 4
+	case glfw.KeyDown, glfw.KeyEnter, glfw.KeyKPEnter, glfw.KeyTab:
+		se.line = (se.line + 1) % 4
+	case glfw.KeyBackspace:
+		if l := se.lines[se.line]; len(l) > 0 {
+			se.lines[se.line] = l[:len(l)-1]
+			se.updateSign()
+		}
+	default:
+		return
+	}
+	se.cursorTick = 0
+	se.updateText()
+}
+
+func (se *signEditor) handleChar(w *glfw.Window, char rune) {
+	// Formatting codes can't be typed on signs
+	if char == '§' || !unicode.IsPrint(char) {
+		return
+	}
+	line := append([]rune(nil), se.lines[se.line]...)
+	line = append(line, char)
+	if render.SizeOfString(string(line)) > signMaxLineWidth {
+		return
+	}
+	se.lines[se.line] = line
+	se.cursorTick = 0
+	se.updateText()
+	se.updateSign()
+}
+
+// done sends the completed sign to the server and returns
+// to the game.
+func (se *signEditor) done() {
+	Client.network.Write(&protocol.SetSign{
+		Location: protocol.NewPosition(se.position.X, se.position.Y, se.position.Z),
+		Line1:    string(se.lines[0]),
+		Line2:    string(se.lines[1]),
+		Line3:    string(se.lines[2]),
+		Line4:    string(se.lines[3]),
+	})
+	setScreen(nil)
+	lockMouse = true
+	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+}
+
+func (se *signEditor) remove() {
+	se.scene.Hide()
+	window.SetKeyCallback(onKey)
+	window.SetCharCallback(onChar)
+}
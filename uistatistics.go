@@ -0,0 +1,219 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/thinkofdeath/steven/protocol"
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/ui"
+	"github.com/thinkofdeath/steven/ui/scene"
+)
+
+const (
+	statListTop        = 90
+	statListWidth      = 600
+	statRowHeight      = 20
+	statColumnWidth    = 100
+	statListBottomSize = 70
+)
+
+type statisticsMenu struct {
+	baseUI
+	scene *scene.Type
+
+	background *ui.Image
+	loading    *ui.Text
+	tabs       [len(statCategories)]*ui.Button
+
+	category       statCategory
+	list           *scene.Type
+	container      *ui.Container
+	listBackground *ui.Image
+	rows           [][]*ui.Text
+	scroll         float64
+	received       bool
+}
+
+func newStatisticsMenu() screen {
+	sm := &statisticsMenu{
+		scene: scene.New(true),
+	}
+
+	sm.background = ui.NewImage(render.GetTexture("solid"), 0, 0, 854, 480, 0, 0, 1, 1, 0, 0, 0)
+	sm.background.SetA(160)
+	sm.scene.AddDrawable(sm.background.Attach(ui.Top, ui.Left))
+
+	title := ui.NewText("Statistics", 0, 10, 255, 255, 255).Attach(ui.Top, ui.Middle)
+	sm.scene.AddDrawable(title)
+
+	for i := range statCategories {
+		cat := statCategory(i)
+		x := float64(i)*150 - float64(len(statCategories)-1)*75
+		btn, txt := newButtonText(statCategories[i].title, x, 40, 140, 30)
+		sm.scene.AddDrawable(btn.Attach(ui.Top, ui.Middle))
+		sm.scene.AddDrawable(txt)
+		btn.AddClick(func() {
+			if btn.Disabled() {
+				return
+			}
+			sm.setCategory(cat)
+		})
+		sm.tabs[i] = btn
+	}
+
+	done, txt := newButtonText("Done", 0, 30, 200, 30)
+	sm.scene.AddDrawable(done.Attach(ui.Bottom, ui.Middle))
+	sm.scene.AddDrawable(txt)
+	done.AddClick(func() { setScreen(newGameMenu()) })
+
+	sm.loading = ui.NewText("Downloading statistics...", 0, 0, 255, 255, 255).Attach(ui.Middle, ui.Center)
+	sm.scene.AddDrawable(sm.loading)
+
+	sm.container = ui.NewContainer(0, statListTop, statListWidth, 0).
+		Attach(ui.Top, ui.Middle)
+
+	uiFooter(sm.scene)
+
+	// Request the latest statistics from the server, the
+	// list is drawn once they arrive
+	Client.network.Write(&protocol.ClientStatus{ActionID: 1})
+	return sm
+}
+
+func (sm *statisticsMenu) init() {
+	window.SetScrollCallback(sm.onScroll)
+	window.SetKeyCallback(sm.handleKey)
+}
+
+// update redraws the current category with the latest
+// statistics from the server.
+func (sm *statisticsMenu) update() {
+	sm.received = true
+	sm.loading.SetDraw(false)
+	sm.setCategory(sm.category)
+}
+
+func (sm *statisticsMenu) setCategory(cat statCategory) {
+	sm.category = cat
+	for i, btn := range sm.tabs {
+		btn.SetDisabled(statCategory(i) == cat)
+	}
+	if !sm.received {
+		return
+	}
+	if sm.list != nil {
+		sm.list.Hide()
+	}
+	sm.list = scene.New(true)
+	sm.rows = sm.rows[:0]
+	sm.scroll = 0
+
+	sm.listBackground = ui.NewImage(render.GetTexture("solid"), 0, 0, statListWidth, 0, 0, 0, 1, 1, 0, 0, 0).
+		Attach(ui.Top, ui.Left)
+	sm.listBackground.SetA(100)
+	sm.listBackground.AttachTo(sm.container)
+	sm.list.AddDrawable(sm.listBackground)
+
+	columns := statCategories[cat].columns
+	if len(columns) > 0 {
+		var header []*ui.Text
+		for i, col := range columns {
+			x := 5 + float64(len(columns)-1-i)*statColumnWidth
+			t := ui.NewText(col.title, x, 0, 255, 255, 160).Attach(ui.Top, ui.Right)
+			header = append(header, t)
+		}
+		sm.addRow(header)
+	}
+
+	for _, row := range statisticRows(cat, Client.statistics) {
+		texts := []*ui.Text{
+			ui.NewText(row.name, 5, 0, 255, 255, 255).Attach(ui.Top, ui.Left),
+		}
+		for i, val := range row.values {
+			x := 5 + float64(len(row.values)-1-i)*statColumnWidth
+			texts = append(texts, ui.NewText(val, x, 0, 255, 255, 255).Attach(ui.Top, ui.Right))
+		}
+		sm.addRow(texts)
+	}
+	sm.list.AddDrawable(sm.container)
+	sm.layout()
+}
+
+func (sm *statisticsMenu) addRow(texts []*ui.Text) {
+	for _, t := range texts {
+		t.AttachTo(sm.container)
+		sm.list.AddDrawable(t)
+	}
+	sm.rows = append(sm.rows, texts)
+}
+
+// layout positions the rows based on the current scroll
+// offset hiding any that are outside of the list.
+func (sm *statisticsMenu) layout() {
+	_, height := window.GetFramebufferSize()
+	listHeight := float64(height)/ui.Scale - statListTop - statListBottomSize
+	if listHeight < statRowHeight {
+		listHeight = statRowHeight
+	}
+	sm.container.SetHeight(listHeight)
+	if sm.list == nil {
+		return
+	}
+	sm.listBackground.SetHeight(listHeight)
+
+	maxScroll := float64(len(sm.rows))*statRowHeight - listHeight
+	if sm.scroll > maxScroll {
+		sm.scroll = maxScroll
+	}
+	if sm.scroll < 0 {
+		sm.scroll = 0
+	}
+	for i, row := range sm.rows {
+		y := float64(i)*statRowHeight - sm.scroll
+		visible := y >= 0 && y+statRowHeight <= listHeight
+		for _, t := range row {
+			t.SetY(y + 1)
+			t.SetDraw(visible)
+		}
+	}
+}
+
+func (sm *statisticsMenu) onScroll(w *glfw.Window, xoff float64, yoff float64) {
+	sm.scroll -= yoff * statRowHeight
+	sm.layout()
+}
+
+func (sm *statisticsMenu) handleKey(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	if key == glfw.KeyEscape && action == glfw.Release {
+		setScreen(newGameMenu())
+	}
+}
+
+func (sm *statisticsMenu) tick(delta float64) {
+	width, height := window.GetFramebufferSize()
+	sm.background.SetWidth(float64(width) / ui.Scale)
+	sm.background.SetHeight(float64(height) / ui.Scale)
+	sm.layout()
+}
+
+func (sm *statisticsMenu) remove() {
+	window.SetScrollCallback(onScroll)
+	window.SetKeyCallback(onKey)
+	sm.scene.Hide()
+	if sm.list != nil {
+		sm.list.Hide()
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anvil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/thinkofdeath/steven/encoding/nbt"
+	"github.com/thinkofdeath/steven/world"
+)
+
+func TestRegion(t *testing.T) {
+	r := &Region{}
+	tag := nbt.NewCompound()
+	tag.Items["test"] = "hello"
+	if err := r.SetChunk(33, -2, tag); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len()%sectorSize != 0 {
+		t.Fatalf("region isn't a whole number of sectors: %d bytes", buf.Len())
+	}
+
+	r, err := ReadRegion(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Chunk(1, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Items["test"] != "hello" {
+		t.Fatalf("chunk wasn't read back correctly: %v", got)
+	}
+	if got, _ := r.Chunk(0, 0); got != nil {
+		t.Fatal("empty chunk returned data")
+	}
+}
+
+func TestEncodeChunk(t *testing.T) {
+	c := world.NewChunk(3, -4)
+	// Blocks above 255 need the add array
+	c.SetBlock(1<<4|2, 1, 17, 2)
+	c.SetBlock(300<<4|5, 0, 16, 0)
+	tag := EncodeChunk(c, func(state uint16) uint16 { return state }, nil)
+
+	level := tag.Items["Level"].(*nbt.Compound)
+	if level.Items["xPos"] != int32(3) || level.Items["zPos"] != int32(-4) {
+		t.Fatalf("wrong position %v, %v", level.Items["xPos"], level.Items["zPos"])
+	}
+	sections := level.Items["Sections"].(*nbt.List)
+	if len(sections.Elements) != 1 {
+		t.Fatalf("wanted 1 section and got %d", len(sections.Elements))
+	}
+	s := sections.Elements[0].(*nbt.Compound)
+	if s.Items["Y"] != int8(1) {
+		t.Fatalf("wrong section y %v", s.Items["Y"])
+	}
+	idx := 1<<8 | 2<<4 | 1
+	if b := s.Items["Blocks"].([]byte)[idx]; b != 1 {
+		t.Fatalf("wanted block 1 and got %d", b)
+	}
+	if d := s.Items["Data"].([]byte)[idx>>1] >> 4; d != 2 {
+		t.Fatalf("wanted data 2 and got %d", d)
+	}
+	if b, add := s.Items["Blocks"].([]byte)[0], s.Items["Add"].([]byte)[0]&0xF; int(b)|int(add)<<8 != 300 {
+		t.Fatalf("wanted block 300 and got %d", int(b)|int(add)<<8)
+	}
+	if h := level.Items["HeightMap"].([]int32)[2<<4|1]; h != 18 {
+		t.Fatalf("wanted height 18 and got %d", h)
+	}
+}
+
+// testRegistry uses the combined id as the state with every
+// block letting light through.
+type testRegistry struct{}
+
+func (testRegistry) StateByCombinedID(id uint16) uint16 { return id }
+func (testRegistry) LightReduction(state uint16) int    { return 0 }
+func (testRegistry) LightEmitted(state uint16) int      { return 0 }
+
+func TestDecodeChunk(t *testing.T) {
+	c := world.NewChunk(-1, 7)
+	c.SetBlock(300<<4|5, 4, 40, 9)
+	c.SetBlock(1<<4, 4, 41, 9)
+	c.Biomes[3] = 6
+	world.LightChunk(c, testRegistry{}, true)
+	sign := nbt.NewCompound()
+	sign.Items["id"] = "Sign"
+	tag := EncodeChunk(c, func(state uint16) uint16 { return state }, []*nbt.Compound{sign})
+
+	got, blockEntities, err := DecodeChunk(tag, testRegistry{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.X != -1 || got.Z != 7 {
+		t.Fatalf("wrong position %d, %d", got.X, got.Z)
+	}
+	if b := got.Block(4, 40, 9); b != 300<<4|5 {
+		t.Fatalf("wanted block %d and got %d", 300<<4|5, b)
+	}
+	if b := got.Block(4, 41, 9); b != 1<<4 {
+		t.Fatalf("wanted block %d and got %d", 1<<4, b)
+	}
+	if got.Biomes[3] != 6 {
+		t.Fatalf("wanted biome 6 and got %d", got.Biomes[3])
+	}
+	if l := got.Sections[2].SkyLight(0, 0, 0); l != 15 {
+		t.Fatalf("wanted sky light 15 and got %d", l)
+	}
+	if len(blockEntities) != 1 || blockEntities[0].Items["id"] != "Sign" {
+		t.Fatalf("block entities weren't decoded: %v", blockEntities)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anvil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	want := Level{Name: "test", SpawnX: -5, SpawnY: 64, GameMode: 1, DayTime: 6000}
+	if err := WriteLevel(dir, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadLevel(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("wanted %+v and got %+v", want, got)
+	}
+}
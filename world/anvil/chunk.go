@@ -0,0 +1,229 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anvil
+
+import (
+	"errors"
+
+	"github.com/thinkofdeath/steven/encoding/nbt"
+	"github.com/thinkofdeath/steven/type/nibble"
+	"github.com/thinkofdeath/steven/world"
+)
+
+// DataVersion is the version of the chunk format written. This
+// matches Minecraft 1.9.
+const DataVersion = 169
+
+var (
+	// ErrInvalidChunk is returned when a chunk's nbt is missing
+	// required information.
+	ErrInvalidChunk = errors.New("invalid chunk")
+)
+
+// EncodeChunk converts the chunk into the nbt stored in a region
+// file. combinedID converts a block state into the block id and
+// data saved (block id << 4 | data). The block entities must
+// already contain their position.
+func EncodeChunk(c *world.Chunk, combinedID func(state uint16) uint16, blockEntities []*nbt.Compound) *nbt.Compound {
+	level := nbt.NewCompound()
+	level.Items["xPos"] = int32(c.X)
+	level.Items["zPos"] = int32(c.Z)
+	level.Items["LastUpdate"] = int64(0)
+	level.Items["InhabitedTime"] = int64(0)
+	level.Items["V"] = int8(1)
+	// The light and terrain are already complete so stop
+	// Minecraft from generating over the top of them
+	level.Items["LightPopulated"] = int8(1)
+	level.Items["TerrainPopulated"] = int8(1)
+
+	biomes := make([]byte, len(c.Biomes))
+	copy(biomes, c.Biomes[:])
+	level.Items["Biomes"] = biomes
+
+	// Minecraft's height map is the first block above the
+	// highest block
+	heightMap := make([]int32, 16*16)
+	for z := 0; z < 16; z++ {
+		for x := 0; x < 16; x++ {
+			h := c.HighestBlock(x, z)
+			if c.Block(x, h, z) != world.Air {
+				h++
+			}
+			heightMap[z<<4|x] = int32(h)
+		}
+	}
+	level.Items["HeightMap"] = heightMap
+
+	sections := &nbt.List{Type: nbt.TagCompound}
+	for y, s := range c.Sections {
+		if s == nil {
+			continue
+		}
+		sections.Elements = append(sections.Elements, encodeSection(s, y, combinedID))
+	}
+	level.Items["Sections"] = sections
+
+	level.Items["Entities"] = &nbt.List{Type: nbt.TagCompound}
+	tiles := &nbt.List{Type: nbt.TagCompound}
+	for _, be := range blockEntities {
+		tiles.Elements = append(tiles.Elements, be)
+	}
+	level.Items["TileEntities"] = tiles
+
+	root := nbt.NewCompound()
+	root.Items["Level"] = level
+	root.Items["DataVersion"] = int32(DataVersion)
+	return root
+}
+
+func encodeSection(s *world.Section, y int, combinedID func(state uint16) uint16) *nbt.Compound {
+	blocks := make([]byte, 16*16*16)
+	add := nibble.New(16 * 16 * 16)
+	data := nibble.New(16 * 16 * 16)
+	blockLight := nibble.New(16 * 16 * 16)
+	skyLight := nibble.New(16 * 16 * 16)
+	needsAdd := false
+	for i := range blocks {
+		x, y, z := i&0xF, i>>8, (i>>4)&0xF
+		id := combinedID(s.Block(x, y, z))
+		blocks[i] = byte(id >> 4)
+		if id>>12 != 0 {
+			nibble.Array(add).Set(i, byte(id>>12))
+			needsAdd = true
+		}
+		nibble.Array(data).Set(i, byte(id&0xF))
+		nibble.Array(blockLight).Set(i, s.BlockLight(x, y, z))
+		nibble.Array(skyLight).Set(i, s.SkyLight(x, y, z))
+	}
+
+	tag := nbt.NewCompound()
+	tag.Items["Y"] = int8(y)
+	tag.Items["Blocks"] = blocks
+	if needsAdd {
+		tag.Items["Add"] = add
+	}
+	tag.Items["Data"] = data
+	tag.Items["BlockLight"] = blockLight
+	tag.Items["SkyLight"] = skyLight
+	return tag
+}
+
+// SaveChunks writes the encoded chunks into the region files in
+// the world's directory. Chunks already saved in the region files
+// that aren't being replaced are kept.
+func SaveChunks(dir string, chunks map[world.ChunkPosition]*nbt.Compound) error {
+	type regionPos struct{ x, z int }
+	byRegion := map[regionPos][]world.ChunkPosition{}
+	for pos := range chunks {
+		rp := regionPos{pos.X >> 5, pos.Z >> 5}
+		byRegion[rp] = append(byRegion[rp], pos)
+	}
+	for _, positions := range byRegion {
+		path := RegionPath(dir, positions[0].X, positions[0].Z)
+		r, err := OpenRegion(path)
+		if err != nil {
+			return err
+		}
+		for _, pos := range positions {
+			if err := r.SetChunk(pos.X, pos.Z, chunks[pos]); err != nil {
+				return err
+			}
+		}
+		if err := r.Save(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeChunk creates a chunk from the nbt stored in a region file
+// returning it and the nbt of its block entities. The lighting is
+// calculated if it wasn't saved with the chunk. Sky light is
+// cleared when sky is false.
+func DecodeChunk(tag *nbt.Compound, reg world.Registry, sky bool) (*world.Chunk, []*nbt.Compound, error) {
+	level, ok := tag.Items["Level"].(*nbt.Compound)
+	if !ok {
+		return nil, nil, ErrInvalidChunk
+	}
+	x, xok := level.Items["xPos"].(int32)
+	z, zok := level.Items["zPos"].(int32)
+	if !xok || !zok {
+		return nil, nil, ErrInvalidChunk
+	}
+	c := world.NewChunk(int(x), int(z))
+
+	if sections, ok := level.Items["Sections"].(*nbt.List); ok {
+		for _, e := range sections.Elements {
+			st, ok := e.(*nbt.Compound)
+			if !ok {
+				return nil, nil, ErrInvalidChunk
+			}
+			if err := decodeSection(c, st, reg); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if biomes, ok := level.Items["Biomes"].([]byte); ok && len(biomes) == len(c.Biomes) {
+		copy(c.Biomes[:], biomes)
+	}
+	c.CalcHeightmap()
+
+	if lit, _ := level.Items["LightPopulated"].(int8); lit == 0 || !sky {
+		world.LightChunk(c, reg, sky)
+	}
+
+	var blockEntities []*nbt.Compound
+	if tiles, ok := level.Items["TileEntities"].(*nbt.List); ok {
+		for _, e := range tiles.Elements {
+			if be, ok := e.(*nbt.Compound); ok {
+				blockEntities = append(blockEntities, be)
+			}
+		}
+	}
+	return c, blockEntities, nil
+}
+
+func decodeSection(c *world.Chunk, tag *nbt.Compound, reg world.Registry) error {
+	y, ok := tag.Items["Y"].(int8)
+	if !ok || y < 0 || y > 15 {
+		return ErrInvalidChunk
+	}
+	blocks, _ := tag.Items["Blocks"].([]byte)
+	data, _ := tag.Items["Data"].([]byte)
+	blockLight, _ := tag.Items["BlockLight"].([]byte)
+	skyLight, _ := tag.Items["SkyLight"].([]byte)
+	add, hasAdd := tag.Items["Add"].([]byte)
+	if len(blocks) != 4096 || len(data) != 2048 || (hasAdd && len(add) != 2048) {
+		return ErrInvalidChunk
+	}
+
+	s := c.Section(int(y))
+	for i := range blocks {
+		bx, by, bz := i&0xF, i>>8, (i>>4)&0xF
+		id := uint16(blocks[i])<<4 | uint16(nibble.Array(data).Get(i))
+		if hasAdd {
+			id |= uint16(nibble.Array(add).Get(i)) << 12
+		}
+		s.SetBlock(reg.StateByCombinedID(id), bx, by, bz)
+		if len(blockLight) == 2048 {
+			s.SetBlockLight(nibble.Array(blockLight).Get(i), bx, by, bz)
+		}
+		if len(skyLight) == 2048 {
+			s.SetSkyLight(nibble.Array(skyLight).Get(i), bx, by, bz)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anvil
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/thinkofdeath/steven/encoding/nbt"
+)
+
+// Level is the information about a world saved in its level.dat.
+type Level struct {
+	Name                   string
+	SpawnX, SpawnY, SpawnZ int
+	GameMode               int
+	Hardcore               bool
+	Difficulty             int
+	// Time is the number of ticks the world has existed for and
+	// DayTime is the time of day in ticks
+	Time, DayTime int64
+}
+
+// WriteLevel writes the level.dat file into the world's directory.
+// The world is set to generate empty chunks so that anything that
+// wasn't saved is left as void.
+func WriteLevel(dir string, l Level) error {
+	data := nbt.NewCompound()
+	data.Items["version"] = int32(19133)
+	data.Items["DataVersion"] = int32(DataVersion)
+	data.Items["LevelName"] = l.Name
+	data.Items["generatorName"] = "flat"
+	data.Items["generatorVersion"] = int32(0)
+	data.Items["generatorOptions"] = "3;minecraft:air;1;"
+	data.Items["MapFeatures"] = int8(0)
+	data.Items["initialized"] = int8(1)
+	data.Items["allowCommands"] = int8(1)
+	data.Items["GameType"] = int32(l.GameMode)
+	data.Items["hardcore"] = boolByte(l.Hardcore)
+	data.Items["Difficulty"] = int8(l.Difficulty)
+	data.Items["SpawnX"] = int32(l.SpawnX)
+	data.Items["SpawnY"] = int32(l.SpawnY)
+	data.Items["SpawnZ"] = int32(l.SpawnZ)
+	data.Items["Time"] = l.Time
+	data.Items["DayTime"] = l.DayTime
+	data.Items["LastPlayed"] = time.Now().UnixNano() / int64(time.Millisecond)
+
+	root := nbt.NewCompound()
+	root.Items["Data"] = data
+	return writeGZipFile(filepath.Join(dir, "level.dat"), root)
+}
+
+func boolByte(b bool) int8 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func writeGZipFile(path string, tag *nbt.Compound) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	if err := writeRoot(gw, tag); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// ReadLevel reads the level.dat file in the world's directory.
+func ReadLevel(dir string) (Level, error) {
+	var l Level
+	f, err := os.Open(filepath.Join(dir, "level.dat"))
+	if err != nil {
+		return l, err
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return l, err
+	}
+	root, err := readRoot(bufio.NewReader(gr))
+	if err != nil {
+		return l, err
+	}
+	data, ok := root.Items["Data"].(*nbt.Compound)
+	if !ok {
+		return l, nbt.ErrInvalidCompound
+	}
+	l.Name, _ = data.Items["LevelName"].(string)
+	l.SpawnX = intItem(data, "SpawnX")
+	l.SpawnY = intItem(data, "SpawnY")
+	l.SpawnZ = intItem(data, "SpawnZ")
+	l.GameMode = intItem(data, "GameType")
+	l.Hardcore = intItem(data, "hardcore") != 0
+	l.Difficulty = intItem(data, "Difficulty")
+	l.Time, _ = data.Items["Time"].(int64)
+	l.DayTime, _ = data.Items["DayTime"].(int64)
+	return l, nil
+}
+
+// intItem returns the integer item with the name whatever
+// size it was saved as.
+func intItem(c *nbt.Compound, name string) int {
+	switch v := c.Items[name].(type) {
+	case int8:
+		return int(v)
+	case int16:
+		return int(v)
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	}
+	return 0
+}
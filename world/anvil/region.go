@@ -0,0 +1,231 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anvil reads and writes worlds saved in Minecraft's
+// Anvil format.
+package anvil
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/thinkofdeath/steven/encoding/nbt"
+)
+
+const (
+	sectorSize = 4096
+
+	compressionGZip = 1
+	compressionZlib = 2
+)
+
+var (
+	// ErrInvalidRegion is returned when a region file's header
+	// points outside of the file.
+	ErrInvalidRegion = errors.New("invalid region file")
+)
+
+// Region is a region file holding up to 32x32 chunks.
+type Region struct {
+	chunks     [32 * 32][]byte
+	timestamps [32 * 32]int32
+}
+
+// RegionPath returns the location of the region file containing
+// the chunk inside the world's directory.
+func RegionPath(dir string, x, z int) string {
+	return filepath.Join(dir, "region", fmt.Sprintf("r.%d.%d.mca", x>>5, z>>5))
+}
+
+// ReadRegion reads a whole region file into memory.
+func ReadRegion(r io.Reader) (*Region, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reg := &Region{}
+	if len(data) == 0 {
+		// Empty files are valid and hold no chunks
+		return reg, nil
+	}
+	if len(data) < sectorSize*2 {
+		return nil, ErrInvalidRegion
+	}
+	for i := range reg.chunks {
+		loc := binary.BigEndian.Uint32(data[i*4:])
+		reg.timestamps[i] = int32(binary.BigEndian.Uint32(data[sectorSize+i*4:]))
+		if loc == 0 {
+			continue
+		}
+		offset := int(loc>>8) * sectorSize
+		if offset+5 > len(data) {
+			return nil, ErrInvalidRegion
+		}
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		if length < 1 || offset+4+length > len(data) {
+			return nil, ErrInvalidRegion
+		}
+		reg.chunks[i] = data[offset+4 : offset+4+length]
+	}
+	return reg, nil
+}
+
+// OpenRegion reads the region file at the path. A missing file
+// is treated as an empty region.
+func OpenRegion(path string) (*Region, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &Region{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadRegion(f)
+}
+
+// Chunk returns the nbt of the chunk at the position relative to
+// the region or nil if the chunk isn't in the region.
+func (r *Region) Chunk(x, z int) (*nbt.Compound, error) {
+	data := r.chunks[(z&31)<<5|(x&31)]
+	if data == nil {
+		return nil, nil
+	}
+	var cr io.Reader
+	var err error
+	switch data[0] {
+	case compressionGZip:
+		cr, err = gzip.NewReader(bytes.NewReader(data[1:]))
+	case compressionZlib:
+		cr, err = zlib.NewReader(bytes.NewReader(data[1:]))
+	default:
+		return nil, fmt.Errorf("unknown compression type %d", data[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The nbt reader reads a byte at a time and doesn't expect
+	// the final byte to come with an EOF which the decompressors
+	// do, buffering avoids both
+	return readRoot(bufio.NewReader(cr))
+}
+
+// SetChunk replaces the chunk at the position relative to the
+// region. Passing nil removes the chunk.
+func (r *Region) SetChunk(x, z int, tag *nbt.Compound) error {
+	idx := (z&31)<<5 | (x & 31)
+	if tag == nil {
+		r.chunks[idx] = nil
+		r.timestamps[idx] = 0
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(compressionZlib)
+	zw := zlib.NewWriter(&buf)
+	if err := writeRoot(zw, tag); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	r.chunks[idx] = buf.Bytes()
+	r.timestamps[idx] = int32(time.Now().Unix())
+	return nil
+}
+
+// WriteTo writes the region in the region file format.
+func (r *Region) WriteTo(w io.Writer) (int64, error) {
+	var header [sectorSize * 2]byte
+	var body bytes.Buffer
+	sector := 2
+	for i, data := range r.chunks {
+		if data == nil {
+			continue
+		}
+		size := (len(data) + 4 + sectorSize - 1) / sectorSize
+		if size > 0xFF {
+			return 0, fmt.Errorf("chunk %d is too large to save", i)
+		}
+		binary.BigEndian.PutUint32(header[i*4:], uint32(sector<<8|size))
+		binary.BigEndian.PutUint32(header[sectorSize+i*4:], uint32(r.timestamps[i]))
+		binary.Write(&body, binary.BigEndian, int32(len(data)))
+		body.Write(data)
+		// Pad to the end of the sector
+		body.Write(make([]byte, size*sectorSize-len(data)-4))
+		sector += size
+	}
+	n, err := w.Write(header[:])
+	if err != nil {
+		return int64(n), err
+	}
+	bn, err := body.WriteTo(w)
+	return int64(n) + bn, err
+}
+
+// Save writes the region to the path, creating any missing
+// directories.
+func (r *Region) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	// Write to a temporary file first so that a failed save
+	// doesn't corrupt the existing region
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := r.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readRoot reads a named root compound.
+func readRoot(r io.Reader) (*nbt.Compound, error) {
+	var id [1]byte
+	if _, err := io.ReadFull(r, id[:]); err != nil {
+		return nil, err
+	}
+	if nbt.TypeID(id[0]) != nbt.TagCompound {
+		return nil, nbt.ErrInvalidCompound
+	}
+	tag := nbt.NewCompound()
+	if err := tag.Deserialize(r); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// writeRoot writes the compound as a named root compound.
+func writeRoot(w io.Writer, tag *nbt.Compound) error {
+	if _, err := w.Write([]byte{byte(nbt.TagCompound)}); err != nil {
+		return err
+	}
+	return tag.Serialize(w)
+}
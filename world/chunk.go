@@ -0,0 +1,313 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package world
+
+import (
+	"sync"
+
+	"github.com/thinkofdeath/steven/type/bit"
+	"github.com/thinkofdeath/steven/type/nibble"
+	"github.com/thinkofdeath/steven/world/biome"
+)
+
+// Chunk is a 16x256x16 column of blocks split into 16 sections.
+type Chunk struct {
+	ChunkPosition
+
+	Sections [16]*Section
+	Biomes   [16 * 16]byte
+
+	heightmap [16 * 16]byte
+}
+
+// NewChunk creates an empty chunk at the passed chunk coordinates.
+// The chunk isn't part of a world until it's added with AddChunk.
+func NewChunk(x, z int) *Chunk {
+	return &Chunk{
+		ChunkPosition: ChunkPosition{X: x, Z: z},
+	}
+}
+
+// Section returns the section at the passed section y coordinate
+// creating it if it doesn't exist.
+func (c *Chunk) Section(y int) *Section {
+	if c.Sections[y] == nil {
+		c.Sections[y] = newSection(y)
+	}
+	return c.Sections[y]
+}
+
+// Block returns the block state at the location relative to
+// the chunk.
+func (c *Chunk) Block(x, y, z int) uint16 {
+	s := y >> 4
+	if s < 0 || s > 15 {
+		return Air
+	}
+	sec := c.Sections[s]
+	if sec == nil {
+		return Air
+	}
+	return sec.Block(x, y&0xF, z)
+}
+
+// SetBlock changes the block at the location relative to the
+// chunk without updating the lighting. Returns whether the
+// block was changed.
+func (c *Chunk) SetBlock(state uint16, x, y, z int) bool {
+	s := y >> 4
+	if s < 0 || s > 15 {
+		return false
+	}
+	if c.Sections[s] == nil && state == Air {
+		return false
+	}
+	sec := c.Section(s)
+	if sec.Block(x, y&0xF, z) == state {
+		return false
+	}
+	delete(sec.BlockEntities, sectionIndex(x, y&0xF, z))
+	sec.SetBlock(state, x, y&0xF, z)
+	if y >= c.HighestBlock(x, z) {
+		c.calcHeightmapAt(x, z)
+	}
+	return true
+}
+
+// Biome returns the biome of the column relative to the chunk.
+func (c *Chunk) Biome(x, z int) *biome.Type {
+	return biome.ById(c.Biomes[z<<4|x])
+}
+
+// HighestBlock returns the y coordinate of the highest non-air
+// block in the column relative to the chunk.
+func (c *Chunk) HighestBlock(x, z int) int {
+	return int(c.heightmap[z<<4|x])
+}
+
+// CalcHeightmap recalculates the highest block of every column
+// in the chunk.
+func (c *Chunk) CalcHeightmap() {
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			c.calcHeightmapAt(x, z)
+		}
+	}
+}
+
+func (c *Chunk) calcHeightmapAt(x, z int) {
+	c.heightmap[z<<4|x] = 0
+	for y := 255; y >= 0; y-- {
+		if c.Block(x, y, z) != Air {
+			c.heightmap[z<<4|x] = byte(y)
+			break
+		}
+	}
+}
+
+// Free returns the chunk's sections to the pool for reuse.
+// The chunk mustn't be used after calling this.
+func (c *Chunk) Free() {
+	for i, s := range c.Sections {
+		if s != nil {
+			sectionPool.Put(s)
+			c.Sections[i] = nil
+		}
+	}
+}
+
+var sectionPool = sync.Pool{
+	New: func() interface{} {
+		return &Section{
+			blockLight: nibble.New(16 * 16 * 16),
+			skyLight:   nibble.New(16 * 16 * 16),
+		}
+	},
+}
+
+var fullBrightLight = nibble.New(16 * 16 * 16)
+
+func init() {
+	for i := range fullBrightLight {
+		fullBrightLight[i] = 0xFF
+	}
+}
+
+// Section is a 16x16x16 section of a chunk.
+type Section struct {
+	Y int
+
+	blocks      *bit.Map
+	nextBlockID int
+	blockMap    []*sectionBlock
+	revBlockMap map[uint16]int
+
+	blockLight nibble.Array
+	skyLight   nibble.Array
+
+	// BlockEntities is keyed by the block's index in the section
+	BlockEntities map[int]BlockEntity
+}
+
+type sectionBlock struct {
+	state uint16
+	count int
+}
+
+func newSection(y int) *Section {
+	s := sectionPool.Get().(*Section)
+	s.Y = y
+	s.BlockEntities = map[int]BlockEntity{}
+	s.clearBlocks()
+
+	copy(s.skyLight, fullBrightLight)
+	for i := range s.blockLight {
+		s.blockLight[i] = 0
+	}
+	return s
+}
+
+// clearBlocks fills the section with air.
+func (s *Section) clearBlocks() {
+	s.blocks = bit.NewMap(4096, 4)
+	s.blockMap = []*sectionBlock{
+		{state: Air, count: -1},
+	}
+	s.revBlockMap = map[uint16]int{Air: 0}
+	s.nextBlockID = 1
+}
+
+// setPalette replaces all of the blocks in the section. Each
+// value in blocks is an index into the palette. The map is used
+// as the section's storage when possible instead of being copied.
+func (s *Section) setPalette(palette []uint16, blocks *bit.Map) {
+	counts := make([]int, len(palette))
+	valid := blocks.Length == 4096
+	for i := 0; valid && i < 4096; i++ {
+		idx := blocks.Get(i)
+		if idx >= len(palette) {
+			valid = false
+			break
+		}
+		counts[idx]++
+	}
+	rev := make(map[uint16]int, len(palette))
+	for i, state := range palette {
+		if _, ok := rev[state]; ok {
+			// The palette can only contain each state once
+			valid = false
+			break
+		}
+		rev[state] = i
+	}
+	if !valid {
+		// Fall back to setting each block, anything outside of
+		// the palette becomes air
+		s.clearBlocks()
+		for i := 0; i < blocks.Length && i < 4096; i++ {
+			state := Air
+			if idx := blocks.Get(i); idx < len(palette) {
+				state = palette[idx]
+			}
+			s.SetBlock(state, i&0xF, i>>8, (i>>4)&0xF)
+		}
+		return
+	}
+
+	s.blocks = blocks
+	s.blockMap = make([]*sectionBlock, len(palette))
+	s.revBlockMap = map[uint16]int{}
+	s.nextBlockID = 0
+	for i, state := range palette {
+		if counts[i] == 0 && state != Air {
+			// Leave unused entries free for reuse
+			continue
+		}
+		s.blockMap[i] = &sectionBlock{state: state, count: counts[i]}
+		s.revBlockMap[state] = i
+	}
+}
+
+func sectionIndex(x, y, z int) int {
+	return (y << 8) | (z << 4) | x
+}
+
+// Block returns the block state at the location relative to
+// the section.
+func (s *Section) Block(x, y, z int) uint16 {
+	idx := s.blocks.Get(sectionIndex(x, y, z))
+	return s.blockMap[idx].state
+}
+
+// SetBlock changes the block state at the location relative to
+// the section.
+func (s *Section) SetBlock(state uint16, x, y, z int) {
+	old := s.Block(x, y, z)
+	if old == state {
+		return
+	}
+	// Remove the old block
+	idx := s.revBlockMap[old]
+	info := s.blockMap[idx]
+	info.count--
+	if info.count <= 0 && info.state != Air {
+		s.blockMap[idx] = nil
+		delete(s.revBlockMap, old)
+		s.nextBlockID = idx
+	}
+
+	idx, ok := s.revBlockMap[state]
+	if !ok {
+		for s.nextBlockID < len(s.blockMap) && s.blockMap[s.nextBlockID] != nil {
+			s.nextBlockID++
+		}
+		if len(s.blockMap) <= s.nextBlockID {
+			s.blockMap = append(s.blockMap, nil)
+		}
+		if s.nextBlockID >= 1<<uint(s.blocks.BitSize) {
+			s.blocks = s.blocks.ResizeBits(s.blocks.BitSize << 1)
+		}
+		s.blockMap[s.nextBlockID] = &sectionBlock{state: state}
+		s.revBlockMap[state] = s.nextBlockID
+		idx = s.nextBlockID
+		s.nextBlockID++
+	}
+	s.blockMap[idx].count++
+	s.blocks.Set(sectionIndex(x, y, z), idx)
+}
+
+// BlockLight returns the block light level at the location
+// relative to the section.
+func (s *Section) BlockLight(x, y, z int) byte {
+	return s.blockLight.Get(sectionIndex(x, y, z))
+}
+
+// SetBlockLight sets the block light level at the location
+// relative to the section.
+func (s *Section) SetBlockLight(l byte, x, y, z int) {
+	s.blockLight.Set(sectionIndex(x, y, z), l)
+}
+
+// SkyLight returns the sky light level at the location relative
+// to the section.
+func (s *Section) SkyLight(x, y, z int) byte {
+	return s.skyLight.Get(sectionIndex(x, y, z))
+}
+
+// SetSkyLight sets the sky light level at the location relative
+// to the section.
+func (s *Section) SetSkyLight(l byte, x, y, z int) {
+	s.skyLight.Set(sectionIndex(x, y, z), l)
+}
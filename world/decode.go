@@ -0,0 +1,117 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package world
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/thinkofdeath/steven/protocol"
+	"github.com/thinkofdeath/steven/type/bit"
+)
+
+// DecodeChunk reads the data of a ChunkData packet into the chunk.
+// Only the sections in the mask are read. Sky light is only sent
+// for worlds with a sky and biomes are only sent with full chunks.
+func DecodeChunk(c *Chunk, reg Registry, r io.Reader, mask int32, sky, full bool) error {
+	var b [1]byte
+	for i := 0; i < 16; i++ {
+		if mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		s := c.Section(i)
+
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+		bitSize := int(b[0])
+
+		var palette []uint16
+		if bitSize <= 8 {
+			count, err := protocol.ReadVarInt(r)
+			if err != nil {
+				return err
+			}
+			palette = make([]uint16, count)
+			for i := range palette {
+				id, err := protocol.ReadVarInt(r)
+				if err != nil {
+					return err
+				}
+				palette[i] = reg.StateByCombinedID(uint16(id))
+			}
+		}
+
+		l, err := protocol.ReadVarInt(r)
+		if err != nil {
+			return err
+		}
+		bits := make([]uint64, l)
+		if err := binary.Read(r, binary.BigEndian, &bits); err != nil {
+			return err
+		}
+
+		m := bit.NewMapFromRaw(bits, bitSize)
+		if palette == nil {
+			palette, m = localPalette(m, reg)
+		}
+		s.setPalette(palette, m)
+
+		if _, err := io.ReadFull(r, s.blockLight); err != nil {
+			return err
+		}
+		if sky {
+			if _, err := io.ReadFull(r, s.skyLight); err != nil {
+				return err
+			}
+		} else {
+			for i := range s.skyLight {
+				s.skyLight[i] = 0x00
+			}
+		}
+	}
+
+	if full {
+		if _, err := io.ReadFull(r, c.Biomes[:]); err != nil {
+			return err
+		}
+	}
+
+	c.CalcHeightmap()
+	return nil
+}
+
+// localPalette converts blocks using the global palette (the
+// combined ids) into a palette just for the section.
+func localPalette(m *bit.Map, reg Registry) ([]uint16, *bit.Map) {
+	var palette []uint16
+	ids := map[int]int{}
+	for i := 0; i < m.Length && i < 4096; i++ {
+		id := m.Get(i)
+		if _, ok := ids[id]; !ok {
+			ids[id] = len(palette)
+			palette = append(palette, reg.StateByCombinedID(uint16(id)))
+		}
+	}
+	size := 4
+	for 1<<uint(size) < len(palette) {
+		size <<= 1
+	}
+	local := bit.NewMap(4096, size)
+	for i := 0; i < m.Length && i < 4096; i++ {
+		local.Set(i, ids[m.Get(i)])
+	}
+	return palette, local
+}
@@ -0,0 +1,361 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package world
+
+import (
+	"github.com/thinkofdeath/steven/type/direction"
+)
+
+// The lighting model:
+//
+// Light moving into a block loses one level plus the block's
+// light reduction. Sky light at full strength moving downwards
+// only loses the block's reduction which lets it fall through
+// air unchanged. Block light is the larger of this and the light
+// the block emits.
+//
+// Light is only stored in sections that exist. Missing sections
+// in a loaded chunk are treated as open sky (sky light 15 and no
+// block light) and so is everything above the world. Unloaded
+// chunks and everything below the world are dark. Light never
+// moves through a location it can't be stored at.
+
+type lightType int
+
+const (
+	lightBlock lightType = iota
+	lightSky
+)
+
+type lightPos struct {
+	x, y, z int
+}
+
+type lightRemoval struct {
+	lightPos
+	level int
+}
+
+// lightEngine updates the light stored in a set of chunks.
+// Updates happen in two passes, first light that may have come
+// from the changed locations is removed and then light is spread
+// back in from the edges of the removed area and any sources.
+type lightEngine struct {
+	reg     Registry
+	chunk   func(x, z int) *Chunk
+	changed func(x, y, z int)
+	sky     bool
+
+	removeQueue []lightRemoval
+	removed     []lightPos
+	spreadQueue []lightPos
+
+	lastChunk *Chunk
+}
+
+// section returns the section that stores the location or nil
+// if the location can't hold light.
+func (le *lightEngine) section(x, y, z int) *Section {
+	if y < 0 || y > 255 {
+		return nil
+	}
+	c := le.lastChunk
+	if c == nil || c.X != x>>4 || c.Z != z>>4 {
+		c = le.chunk(x>>4, z>>4)
+		if c == nil {
+			return nil
+		}
+		le.lastChunk = c
+	}
+	return c.Sections[y>>4]
+}
+
+// get returns the light at the location and whether the location
+// can store light.
+func (le *lightEngine) get(t lightType, x, y, z int) (int, bool) {
+	if y > 255 {
+		if t == lightSky {
+			return 15, false
+		}
+		return 0, false
+	}
+	if s := le.section(x, y, z); s != nil {
+		if t == lightSky {
+			return int(s.SkyLight(x&0xF, y&0xF, z&0xF)), true
+		}
+		return int(s.BlockLight(x&0xF, y&0xF, z&0xF)), true
+	}
+	if t == lightSky && y >= 0 && le.lastChunk != nil && le.lastChunk.X == x>>4 && le.lastChunk.Z == z>>4 {
+		// Missing section in a loaded chunk
+		return 15, false
+	}
+	return 0, false
+}
+
+func (le *lightEngine) set(t lightType, l, x, y, z int) {
+	s := le.section(x, y, z)
+	if t == lightSky {
+		s.SetSkyLight(byte(l), x&0xF, y&0xF, z&0xF)
+	} else {
+		s.SetBlockLight(byte(l), x&0xF, y&0xF, z&0xF)
+	}
+	if le.changed != nil {
+		le.changed(x, y, z)
+	}
+}
+
+func (le *lightEngine) block(x, y, z int) uint16 {
+	s := le.section(x, y, z)
+	if s == nil {
+		return Air
+	}
+	return s.Block(x&0xF, y&0xF, z&0xF)
+}
+
+// spread returns the light that moving light of the level in the
+// direction leaves in a block with the passed reduction.
+func spread(t lightType, level int, d direction.Type, reduction int) int {
+	if t == lightSky && d == direction.Down && level == 15 {
+		level -= reduction
+	} else {
+		level -= 1 + reduction
+	}
+	if level < 0 {
+		return 0
+	}
+	return level
+}
+
+// compute returns the light the location should have based on
+// its neighbors and what it emits.
+func (le *lightEngine) compute(t lightType, x, y, z int) int {
+	b := le.block(x, y, z)
+	reduction := le.reg.LightReduction(b)
+	l := 0
+	if t == lightBlock {
+		l = le.reg.LightEmitted(b)
+	}
+	for _, d := range direction.Values {
+		ox, oy, oz := d.Offset()
+		nl, _ := le.get(t, x-ox, y-oy, z-oz)
+		if nl = spread(t, nl, d, reduction); nl > l {
+			l = nl
+		}
+	}
+	return l
+}
+
+func (le *lightEngine) types() []lightType {
+	if le.sky {
+		return []lightType{lightBlock, lightSky}
+	}
+	return []lightType{lightBlock}
+}
+
+// update relights the area around the changed locations.
+func (le *lightEngine) update(changed []lightPos) {
+	for _, t := range le.types() {
+		for _, p := range changed {
+			l, ok := le.get(t, p.x, p.y, p.z)
+			if !ok {
+				continue
+			}
+			le.set(t, 0, p.x, p.y, p.z)
+			le.removeQueue = append(le.removeQueue, lightRemoval{p, l})
+			le.removed = append(le.removed, p)
+		}
+		le.remove(t)
+		le.propagate(t)
+	}
+}
+
+// remove clears any light that may have come from the locations
+// in the remove queue. Neighbors that are bright enough to have
+// their own source are queued to spread their light back.
+func (le *lightEngine) remove(t lightType) {
+	for len(le.removeQueue) > 0 {
+		r := le.removeQueue[0]
+		le.removeQueue = le.removeQueue[1:]
+		for _, d := range direction.Values {
+			ox, oy, oz := d.Offset()
+			n := lightPos{r.x + ox, r.y + oy, r.z + oz}
+			nl, ok := le.get(t, n.x, n.y, n.z)
+			if !ok || nl == 0 {
+				continue
+			}
+			if nl < r.level || (t == lightSky && d == direction.Down && nl == 15 && r.level == 15) {
+				le.set(t, 0, n.x, n.y, n.z)
+				le.removeQueue = append(le.removeQueue, lightRemoval{n, nl})
+				le.removed = append(le.removed, n)
+			} else {
+				le.spreadQueue = append(le.spreadQueue, n)
+			}
+		}
+	}
+	// Sources and locations next to light that isn't stored
+	// won't be refilled by their neighbors
+	for _, p := range le.removed {
+		if l := le.compute(t, p.x, p.y, p.z); l > 0 {
+			le.set(t, l, p.x, p.y, p.z)
+			le.spreadQueue = append(le.spreadQueue, p)
+		}
+	}
+	le.removed = le.removed[:0]
+}
+
+// propagate spreads the light of the locations in the spread
+// queue into their neighbors.
+func (le *lightEngine) propagate(t lightType) {
+	for len(le.spreadQueue) > 0 {
+		p := le.spreadQueue[0]
+		le.spreadQueue = le.spreadQueue[1:]
+		l, _ := le.get(t, p.x, p.y, p.z)
+		if l == 0 {
+			continue
+		}
+		for _, d := range direction.Values {
+			ox, oy, oz := d.Offset()
+			nx, ny, nz := p.x+ox, p.y+oy, p.z+oz
+			nl, ok := le.get(t, nx, ny, nz)
+			if !ok {
+				continue
+			}
+			if l := spread(t, l, d, le.reg.LightReduction(le.block(nx, ny, nz))); l > nl {
+				le.set(t, l, nx, ny, nz)
+				le.spreadQueue = append(le.spreadQueue, lightPos{nx, ny, nz})
+			}
+		}
+	}
+	le.removeQueue = le.removeQueue[:0]
+	le.spreadQueue = le.spreadQueue[:0]
+}
+
+// LightChunk calculates all of the lighting in the chunk on its
+// own, ignoring the chunks around it. The light between chunks is
+// spread when the chunk is added to a world. Sky light is cleared
+// when sky is false.
+func LightChunk(c *Chunk, reg Registry, sky bool) {
+	le := &lightEngine{
+		reg: reg,
+		chunk: func(x, z int) *Chunk {
+			if x == c.X && z == c.Z {
+				return c
+			}
+			return nil
+		},
+		sky: sky,
+	}
+	for _, s := range c.Sections {
+		if s == nil {
+			continue
+		}
+		for i := range s.blockLight {
+			s.blockLight[i] = 0
+			s.skyLight[i] = 0
+		}
+	}
+	for _, t := range le.types() {
+		// Work from the top down so that the sky light falls
+		// through the chunk in a single pass
+		for sy := 15; sy >= 0; sy-- {
+			if c.Sections[sy] == nil {
+				continue
+			}
+			for y := 15; y >= 0; y-- {
+				for z := 0; z < 16; z++ {
+					for x := 0; x < 16; x++ {
+						wx, wy, wz := c.X<<4|x, sy<<4|y, c.Z<<4|z
+						if l := le.compute(t, wx, wy, wz); l > 0 {
+							le.set(t, l, wx, wy, wz)
+							le.spreadQueue = append(le.spreadQueue, lightPos{wx, wy, wz})
+						}
+					}
+				}
+			}
+		}
+		le.propagate(t)
+	}
+}
+
+// lightEngine returns an engine for updating the world's
+// lighting.
+func (w *World) lightEngine() *lightEngine {
+	return &lightEngine{
+		reg:     w.Registry,
+		chunk:   w.Chunk,
+		changed: w.lightChanged,
+		sky:     w.HasSky,
+	}
+}
+
+// relight updates the lighting around the changed locations.
+func (w *World) relight(changed []lightPos) {
+	w.lightEngine().update(changed)
+}
+
+// spreadEdges spreads the light across the edges of the chunk
+// into its loaded neighbors and back.
+func (w *World) spreadEdges(c *Chunk) {
+	le := w.lightEngine()
+	for _, t := range le.types() {
+		for _, d := range []direction.Type{direction.North, direction.South, direction.East, direction.West} {
+			ox, _, oz := d.Offset()
+			n := w.Chunk(c.X+ox, c.Z+oz)
+			if n == nil {
+				continue
+			}
+			// The column of blocks on either side of the edge
+			x, z := c.X<<4, c.Z<<4
+			dx, dz := 1, 0
+			switch d {
+			case direction.North:
+				z -= 1
+			case direction.South:
+				z += 16
+			case direction.West:
+				x -= 1
+				dx, dz = 0, 1
+			case direction.East:
+				x += 16
+				dx, dz = 0, 1
+			}
+			for i := 0; i < 16; i++ {
+				for y := 0; y < 256; y++ {
+					px, pz := x+dx*i, z+dz*i
+					le.spreadQueue = append(le.spreadQueue,
+						lightPos{px, y, pz},
+						lightPos{px - ox, y, pz - oz},
+					)
+				}
+			}
+		}
+		le.propagate(t)
+	}
+}
+
+// lightChanged marks the sections that draw the location as
+// changed. Sections sample the light of the blocks around them
+// so the neighboring sections need redrawing too when the
+// location is on an edge.
+func (w *World) lightChanged(x, y, z int) {
+	w.changed(x, y, z)
+	for _, d := range direction.Values {
+		ox, oy, oz := d.Offset()
+		nx, ny, nz := x+ox, y+oy, z+oz
+		if nx>>4 != x>>4 || ny>>4 != y>>4 || nz>>4 != z>>4 {
+			w.changed(nx, ny, nz)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package world
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const testSize = 3
+
+// generateWorld creates a small world of hills with caves,
+// leaves and glowstone scattered through it. The lighting is
+// calculated by the world as the chunks are added.
+func generateWorld(seed int64) (*World, *rand.Rand) {
+	r := rand.New(rand.NewSource(seed))
+	w := New(testRegistry{})
+	for cx := 0; cx < testSize; cx++ {
+		for cz := 0; cz < testSize; cz++ {
+			c := NewChunk(cx, cz)
+			for x := 0; x < 16; x++ {
+				for z := 0; z < 16; z++ {
+					height := 20 + r.Intn(24)
+					for y := 0; y < height; y++ {
+						state := uint16(testStone)
+						switch n := r.Intn(100); {
+						case n < 15:
+							state = Air
+						case n < 17:
+							state = testLeaves
+						case n < 18:
+							state = testGlowstone
+						}
+						c.SetBlock(state, x, y, z)
+					}
+				}
+			}
+			LightChunk(c, testRegistry{}, true)
+			w.AddChunk(c)
+		}
+	}
+	return w, r
+}
+
+// referenceLight calculates the light of every location that
+// can store light from scratch by repeatedly brightening each
+// location until nothing changes. Locations that can't store
+// light are -1.
+func referenceLight(w *World, t lightType) []int {
+	const size = testSize * 16
+	index := func(x, y, z int) int { return (y*size+z)*size + x }
+	light := make([]int, size*256*size)
+	reduction := make([]int, len(light))
+	emitted := make([]int, len(light))
+	for x := 0; x < size; x++ {
+		for z := 0; z < size; z++ {
+			c := w.Chunk(x>>4, z>>4)
+			for y := 0; y < 256; y++ {
+				i := index(x, y, z)
+				light[i] = -1
+				if c.Sections[y>>4] != nil {
+					light[i] = 0
+				}
+				b := w.Block(x, y, z)
+				reduction[i] = w.Registry.LightReduction(b)
+				if t == lightBlock {
+					emitted[i] = w.Registry.LightEmitted(b)
+				}
+			}
+		}
+	}
+	lookup := func(x, y, z int) int {
+		if x < 0 || x >= size || z < 0 || z >= size || y < 0 {
+			return 0
+		}
+		if y > 255 {
+			if t == lightSky {
+				return 15
+			}
+			return 0
+		}
+		if l := light[index(x, y, z)]; l != -1 {
+			return l
+		}
+		// Missing section
+		if t == lightSky {
+			return 15
+		}
+		return 0
+	}
+	offsets := [][3]int{{0, 1, 0}, {0, -1, 0}, {1, 0, 0}, {-1, 0, 0}, {0, 0, 1}, {0, 0, -1}}
+	for changed := true; changed; {
+		changed = false
+		for i := range light {
+			// Alternate directions to converge faster
+			if i&1 == 1 {
+				i = len(light) - i
+			}
+			if light[i] == -1 {
+				continue
+			}
+			x, y, z := i%size, i/(size*size), (i/size)%size
+			best := emitted[i]
+			for _, o := range offsets {
+				l := lookup(x+o[0], y+o[1], z+o[2])
+				if t == lightSky && o[1] == 1 && l == 15 {
+					l -= reduction[i]
+				} else {
+					l -= 1 + reduction[i]
+				}
+				if l > best {
+					best = l
+				}
+			}
+			if best > light[i] {
+				light[i] = best
+				changed = true
+			}
+		}
+	}
+	return light
+}
+
+func checkLight(t *testing.T, w *World, when string) {
+	const size = testSize * 16
+	for _, lt := range []lightType{lightBlock, lightSky} {
+		name, get := "block", w.BlockLight
+		if lt == lightSky {
+			name, get = "sky", w.SkyLight
+		}
+		wrong := 0
+		for i, l := range referenceLight(w, lt) {
+			if l == -1 {
+				continue
+			}
+			x, y, z := i%size, i/(size*size), (i/size)%size
+			if got := get(x, y, z); got != l {
+				if wrong < 5 {
+					t.Errorf("%s: %s light at %d,%d,%d wanted %d and got %d", when, name, x, y, z, l, got)
+				}
+				wrong++
+			}
+		}
+		if wrong > 0 {
+			t.Fatalf("%s: %d incorrect %s light levels", when, wrong, name)
+		}
+	}
+}
+
+func randomState(r *rand.Rand) uint16 {
+	switch r.Intn(8) {
+	case 0:
+		return testGlowstone
+	case 1:
+		return testLeaves
+	case 2, 3, 4:
+		return testStone
+	}
+	return Air
+}
+
+func TestLightChunk(t *testing.T) {
+	for seed := int64(0); seed < 3; seed++ {
+		w, _ := generateWorld(seed)
+		checkLight(t, w, "generated")
+	}
+}
+
+func TestLightSetBlock(t *testing.T) {
+	w, r := generateWorld(42)
+	for i := 0; i < 40; i++ {
+		x, y, z := r.Intn(testSize*16), r.Intn(56), r.Intn(testSize*16)
+		w.SetBlock(randomState(r), x, y, z)
+		if i%8 == 7 {
+			checkLight(t, w, "after SetBlock")
+		}
+	}
+	// Digging a shaft down into the ground lets the sky in
+	for y := 60; y >= 0; y-- {
+		w.SetBlock(Air, 20, y, 20)
+	}
+	checkLight(t, w, "after digging")
+	// Covering it back over removes it again
+	w.SetBlock(testStone, 20, 50, 20)
+	checkLight(t, w, "after covering")
+}
+
+func TestLightBatch(t *testing.T) {
+	w, r := generateWorld(7)
+	for i := 0; i < 5; i++ {
+		w.Batch(func() {
+			for j := 0; j < 50; j++ {
+				x, y, z := r.Intn(testSize*16), r.Intn(56), r.Intn(testSize*16)
+				w.SetBlock(randomState(r), x, y, z)
+			}
+		})
+		checkLight(t, w, "after Batch")
+	}
+}
+
+func TestLightNoSky(t *testing.T) {
+	w := New(testRegistry{})
+	w.HasSky = false
+	c := NewChunk(0, 0)
+	c.SetBlock(testGlowstone, 8, 8, 8)
+	LightChunk(c, testRegistry{}, false)
+	w.AddChunk(c)
+	if l := w.SkyLight(8, 9, 8); l != 0 {
+		t.Fatalf("SkyLight wanted 0 and got %d", l)
+	}
+	if l := w.BlockLight(8, 9, 8); l != 14 {
+		t.Fatalf("BlockLight wanted 14 and got %d", l)
+	}
+	w.SetBlock(Air, 8, 8, 8)
+	if l := w.BlockLight(8, 9, 8); l != 0 {
+		t.Fatalf("BlockLight after removing the glowstone wanted 0 and got %d", l)
+	}
+}
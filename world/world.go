@@ -0,0 +1,260 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package world provides storage for a Minecraft world's chunks,
+// blocks, lighting and biomes without depending on any rendering
+// code.
+package world
+
+import (
+	"github.com/thinkofdeath/steven/type/direction"
+	"github.com/thinkofdeath/steven/world/biome"
+)
+
+// Air is the block state used for empty blocks. Registries
+// must always use this state for air.
+const Air uint16 = 0
+
+// Registry provides the world with the information it needs
+// about block states. Apart from Air states are opaque to the
+// world.
+type Registry interface {
+	// StateByCombinedID returns the state for the protocol's
+	// block id (block id << 4 | data).
+	StateByCombinedID(id uint16) uint16
+	// LightReduction returns the amount light is reduced by
+	// when passing through the state.
+	LightReduction(state uint16) int
+	// LightEmitted returns the amount of light the state
+	// gives off.
+	LightEmitted(state uint16) int
+}
+
+// BlockEntity is any value attached to a block in the world.
+// The world only stores them, creating and updating them is
+// left to the user of the world.
+type BlockEntity interface{}
+
+// ChunkPosition is the location of a chunk column in chunk
+// coordinates.
+type ChunkPosition struct {
+	X, Z int
+}
+
+// World is a collection of loaded chunks.
+type World struct {
+	Registry Registry
+	// SectionChanged, if set, is called whenever the blocks or
+	// lighting in a chunk section are modified.
+	SectionChanged func(x, y, z int)
+	// HasSky controls whether sky light is calculated for the
+	// world. The nether and the end don't have a sky.
+	HasSky bool
+
+	chunks map[ChunkPosition]*Chunk
+
+	batching     bool
+	batchChanges []lightPos
+}
+
+// New creates an empty world using the passed registry for
+// block information.
+func New(reg Registry) *World {
+	return &World{
+		Registry: reg,
+		HasSky:   true,
+		chunks:   map[ChunkPosition]*Chunk{},
+	}
+}
+
+// Chunk returns the chunk at the passed chunk coordinates or
+// nil if it isn't loaded.
+func (w *World) Chunk(x, z int) *Chunk {
+	return w.chunks[ChunkPosition{x, z}]
+}
+
+// AddChunk adds the chunk to the world replacing any chunk
+// that was at the same position. Light is spread between the
+// chunk and its neighbors.
+func (w *World) AddChunk(c *Chunk) {
+	w.chunks[c.ChunkPosition] = c
+	w.spreadEdges(c)
+}
+
+// RemoveChunk removes and returns the chunk at the passed
+// chunk coordinates.
+func (w *World) RemoveChunk(x, z int) *Chunk {
+	pos := ChunkPosition{x, z}
+	c := w.chunks[pos]
+	delete(w.chunks, pos)
+	return c
+}
+
+// Chunks returns all of the loaded chunks in no specific order.
+func (w *World) Chunks() []*Chunk {
+	out := make([]*Chunk, 0, len(w.chunks))
+	for _, c := range w.chunks {
+		out = append(out, c)
+	}
+	return out
+}
+
+// Block returns the block state at the location. Air is
+// returned for locations that aren't loaded.
+func (w *World) Block(x, y, z int) uint16 {
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil {
+		return Air
+	}
+	return c.Block(x&0xF, y, z&0xF)
+}
+
+// SetBlock changes the block at the location and updates the
+// lighting around it. Any block entity at the location is
+// removed.
+func (w *World) SetBlock(state uint16, x, y, z int) {
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil || y < 0 || y > 255 {
+		return
+	}
+	newSection := c.Sections[y>>4] == nil
+	if !c.SetBlock(state, x&0xF, y, z&0xF) {
+		return
+	}
+	changes := []lightPos{{x, y, z}}
+	if newSection {
+		// The section had no light stored before so it all
+		// needs calculating
+		changes = changes[:0]
+		for i := 0; i < 16*16*16; i++ {
+			changes = append(changes, lightPos{x&^0xF | i&0xF, y&^0xF | i>>8, z&^0xF | (i>>4)&0xF})
+		}
+	}
+	if w.batching {
+		w.batchChanges = append(w.batchChanges, changes...)
+	} else {
+		w.relight(changes)
+	}
+	w.changed(x, y, z)
+	for _, d := range direction.Values {
+		ox, oy, oz := d.Offset()
+		w.changed(x+ox, y+oy, z+oz)
+	}
+}
+
+// Batch calls f delaying any lighting updates caused by it
+// until it returns. This is faster than updating the lighting
+// after every change when lots of blocks are changed at once.
+func (w *World) Batch(f func()) {
+	if w.batching {
+		f()
+		return
+	}
+	w.batching = true
+	f()
+	w.batching = false
+	changes := w.batchChanges
+	w.batchChanges = nil
+	if len(changes) > 0 {
+		w.relight(changes)
+	}
+}
+
+// BlockLight returns the block light level at the location.
+func (w *World) BlockLight(x, y, z int) int {
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil || y < 0 || y > 255 {
+		return 0
+	}
+	if s := c.Sections[y>>4]; s != nil {
+		return int(s.BlockLight(x&0xF, y&0xF, z&0xF))
+	}
+	return 0
+}
+
+// SkyLight returns the sky light level at the location.
+func (w *World) SkyLight(x, y, z int) int {
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil || y < 0 || y > 255 {
+		return 15
+	}
+	if s := c.Sections[y>>4]; s != nil {
+		return int(s.SkyLight(x&0xF, y&0xF, z&0xF))
+	}
+	return 15
+}
+
+// Biome returns the biome at the location.
+func (w *World) Biome(x, z int) *biome.Type {
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil {
+		return biome.Invalid
+	}
+	return c.Biome(x&0xF, z&0xF)
+}
+
+// HighestBlockAt returns the y coordinate of the highest
+// non-air block in the column.
+func (w *World) HighestBlockAt(x, z int) int {
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil {
+		return 0
+	}
+	return c.HighestBlock(x&0xF, z&0xF)
+}
+
+// BlockEntity returns the block entity at the location if any.
+func (w *World) BlockEntity(x, y, z int) BlockEntity {
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil || y < 0 || y > 255 {
+		return nil
+	}
+	s := c.Sections[y>>4]
+	if s == nil {
+		return nil
+	}
+	return s.BlockEntities[sectionIndex(x&0xF, y&0xF, z&0xF)]
+}
+
+// SetBlockEntity sets the block entity at the location. Passing
+// nil removes the current one.
+func (w *World) SetBlockEntity(x, y, z int, be BlockEntity) {
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil || y < 0 || y > 255 {
+		return
+	}
+	s := c.Sections[y>>4]
+	if s == nil {
+		return
+	}
+	idx := sectionIndex(x&0xF, y&0xF, z&0xF)
+	if be == nil {
+		delete(s.BlockEntities, idx)
+		return
+	}
+	s.BlockEntities[idx] = be
+}
+
+// changed reports a change to the section containing the
+// location.
+func (w *World) changed(x, y, z int) {
+	if w.SectionChanged == nil || y < 0 || y > 255 {
+		return
+	}
+	c := w.Chunk(x>>4, z>>4)
+	if c == nil || c.Sections[y>>4] == nil {
+		return
+	}
+	w.SectionChanged(x>>4, y>>4, z>>4)
+}
@@ -0,0 +1,218 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package world
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/thinkofdeath/steven/protocol"
+)
+
+// testRegistry uses the combined id as the state. Stone (1) is
+// opaque, leaves (18) dim light slightly and glowstone (89) emits
+// light.
+type testRegistry struct{}
+
+const (
+	testStone     = 1 << 4
+	testLeaves    = 18 << 4
+	testGlowstone = 89 << 4
+)
+
+func (testRegistry) StateByCombinedID(id uint16) uint16 { return id }
+
+func (testRegistry) LightReduction(state uint16) int {
+	switch state {
+	case testStone:
+		return 15
+	case testLeaves:
+		return 1
+	}
+	return 0
+}
+
+func (testRegistry) LightEmitted(state uint16) int {
+	if state == testGlowstone {
+		return 15
+	}
+	return 0
+}
+
+func TestSetBlock(t *testing.T) {
+	w := New(testRegistry{})
+	w.AddChunk(NewChunk(0, 0))
+	// Enough unique states to force the section to grow
+	for i := 0; i < 64; i++ {
+		w.SetBlock(uint16(i+1)<<4, i&0xF, i>>4, 3)
+	}
+	for i := 0; i < 64; i++ {
+		if b := w.Block(i&0xF, i>>4, 3); b != uint16(i+1)<<4 {
+			t.Fatalf("Block(%d, %d, 3) wanted %d and got %d", i&0xF, i>>4, (i+1)<<4, b)
+		}
+	}
+	if h := w.HighestBlockAt(0, 3); h != 3 {
+		t.Fatalf("HighestBlockAt(0, 3) wanted 3 and got %d", h)
+	}
+	w.SetBlock(Air, 0, 3, 3)
+	if h := w.HighestBlockAt(0, 3); h != 2 {
+		t.Fatalf("HighestBlockAt(0, 3) wanted 2 and got %d", h)
+	}
+	if b := w.Block(100, 0, 100); b != Air {
+		t.Fatalf("Block in unloaded chunk wanted air and got %d", b)
+	}
+}
+
+func TestBlockLight(t *testing.T) {
+	w := New(testRegistry{})
+	w.AddChunk(NewChunk(0, 0))
+	w.AddChunk(NewChunk(1, 0))
+	// Lighting is only stored for sections that exist
+	w.SetBlock(testStone, 0, 0, 0)
+	w.SetBlock(testStone, 31, 0, 15)
+	w.SetBlock(testGlowstone, 15, 8, 8)
+	tests := []struct {
+		x, y, z int
+		light   int
+	}{
+		{15, 8, 8, 15},
+		{14, 8, 8, 14},
+		{16, 8, 8, 14},
+		{20, 8, 8, 10},
+		{15, 12, 10, 9},
+		{0, 0, 0, 0},
+	}
+	for _, test := range tests {
+		if l := w.BlockLight(test.x, test.y, test.z); l != test.light {
+			t.Errorf("BlockLight(%d, %d, %d) wanted %d and got %d", test.x, test.y, test.z, test.light, l)
+		}
+	}
+
+	var changed []int
+	w.SectionChanged = func(x, y, z int) {
+		changed = append(changed, x)
+	}
+	w.SetBlock(Air, 15, 8, 8)
+	var sawNeighbor bool
+	for _, x := range changed {
+		if x == 1 {
+			sawNeighbor = true
+		}
+	}
+	if !sawNeighbor {
+		t.Error("Expected the neighboring chunk to be marked as changed")
+	}
+}
+
+func TestDecodeChunk(t *testing.T) {
+	var buf bytes.Buffer
+	// Single section at y=1 using a two entry palette
+	buf.WriteByte(4)
+	protocol.WriteVarInt(&buf, 2)
+	protocol.WriteVarInt(&buf, 0)
+	protocol.WriteVarInt(&buf, testStone)
+	// 4 bits per block with the bottom layer set to stone
+	bits := make([]uint64, 4096*4/64)
+	for i := 0; i < 256/16; i++ {
+		bits[i] = 0x1111111111111111
+	}
+	protocol.WriteVarInt(&buf, protocol.VarInt(len(bits)))
+	binary.Write(&buf, binary.BigEndian, bits)
+	buf.Write(bytes.Repeat([]byte{0x00}, 2048))
+	buf.Write(bytes.Repeat([]byte{0xFF}, 2048))
+	biomes := make([]byte, 256)
+	biomes[0] = 4
+	buf.Write(biomes)
+
+	c := NewChunk(2, 3)
+	if err := DecodeChunk(c, testRegistry{}, &buf, 1<<1, true, true); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("%d bytes left unread", buf.Len())
+	}
+	if c.Sections[0] != nil {
+		t.Fatal("Section 0 wasn't sent but was created")
+	}
+	if b := c.Block(5, 16, 5); b != testStone {
+		t.Fatalf("Block(5, 16, 5) wanted stone and got %d", b)
+	}
+	if b := c.Block(5, 17, 5); b != Air {
+		t.Fatalf("Block(5, 17, 5) wanted air and got %d", b)
+	}
+	if h := c.HighestBlock(5, 5); h != 16 {
+		t.Fatalf("HighestBlock(5, 5) wanted 16 and got %d", h)
+	}
+	if c.Biomes[0] != 4 {
+		t.Fatalf("Biome wanted 4 and got %d", c.Biomes[0])
+	}
+}
+
+func TestDecodeChunkPalette(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    int
+		palette []int
+	}{
+		{"global", 13, nil},
+		{"duplicate", 4, []int{0, testStone, testStone}},
+		{"out of range", 4, []int{0}},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		buf.WriteByte(byte(test.bits))
+		if test.palette != nil {
+			protocol.WriteVarInt(&buf, protocol.VarInt(len(test.palette)))
+			for _, id := range test.palette {
+				protocol.WriteVarInt(&buf, protocol.VarInt(id))
+			}
+		}
+		// Stone in the first block, air everywhere else
+		bits := make([]uint64, 4096*test.bits/64)
+		if test.palette == nil {
+			bits[0] = testStone
+		} else {
+			bits[0] = 1
+		}
+		protocol.WriteVarInt(&buf, protocol.VarInt(len(bits)))
+		binary.Write(&buf, binary.BigEndian, bits)
+		buf.Write(make([]byte, 2048*2))
+
+		c := NewChunk(0, 0)
+		if err := DecodeChunk(c, testRegistry{}, &buf, 1, true, false); err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		want := uint16(testStone)
+		if test.name == "out of range" {
+			want = Air
+		}
+		if b := c.Block(0, 0, 0); b != want {
+			t.Fatalf("%s: Block(0, 0, 0) wanted %d and got %d", test.name, want, b)
+		}
+		// The section must still be able to grow afterwards
+		for i := 0; i < 40; i++ {
+			c.SetBlock(uint16(i+2)<<4, i&0xF, 1, i>>4)
+		}
+		for i := 0; i < 40; i++ {
+			if b := c.Block(i&0xF, 1, i>>4); b != uint16(i+2)<<4 {
+				t.Fatalf("%s: Block(%d, 1, %d) wanted %d and got %d", test.name, i&0xF, i>>4, (i+2)<<4, b)
+			}
+		}
+		if b := c.Block(0, 0, 0); b != want {
+			t.Fatalf("%s: Block(0, 0, 0) changed to %d", test.name, b)
+		}
+	}
+}
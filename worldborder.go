@@ -0,0 +1,277 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"math"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/thinkofdeath/steven/render"
+	"github.com/thinkofdeath/steven/type/vmath"
+	"github.com/thinkofdeath/steven/ui"
+	"github.com/thinkofdeath/steven/ui/scene"
+)
+
+const (
+	// How close the player has to be before the border
+	// wall starts to be drawn
+	borderViewDistance = 32.0
+	// The border wall is built out of segments of this size
+	// as the texture offsets can't hold large values.
+	borderSegmentSize  = 8
+	borderSegmentCount = 8
+	// The number of blocks the forcefield texture covers
+	borderTextureScale = 2.0
+)
+
+type worldBorder struct {
+	centerX, centerZ float64
+
+	oldDiameter, newDiameter float64
+	lerpStart                time.Time
+	lerpDuration             time.Duration
+
+	portalBoundary int
+	warningTime    int
+	warningBlocks  int
+
+	model *render.Model
+	tint  *ui.Image
+}
+
+func (w *worldBorder) init(scene *scene.Type) {
+	// Vanilla's defaults until the server tells us otherwise
+	w.oldDiameter = 60000000
+	w.newDiameter = 60000000
+	w.portalBoundary = 29999984
+	w.warningTime = 15
+	w.warningBlocks = 5
+
+	w.tint = ui.NewImage(render.GetTexture("solid"), 0, 0, 854, 480, 0, 0, 1, 1, 255, 0, 0)
+	w.tint.SetA(0)
+	w.tint.SetDraw(false)
+	scene.AddDrawable(w.tint.Attach(ui.Top, ui.Left))
+}
+
+func (w *worldBorder) free() {
+	if w.model != nil {
+		w.model.Free()
+		w.model = nil
+	}
+}
+
+// setSize instantly changes the diameter of the border.
+func (w *worldBorder) setSize(diameter float64) {
+	w.oldDiameter = diameter
+	w.newDiameter = diameter
+	w.lerpDuration = 0
+}
+
+// lerpSize changes the diameter of the border from old to new
+// over the passed number of milliseconds.
+func (w *worldBorder) lerpSize(old, new float64, speed int64) {
+	w.oldDiameter = old
+	w.newDiameter = new
+	w.lerpStart = time.Now()
+	w.lerpDuration = time.Duration(speed) * time.Millisecond
+	if speed <= 0 {
+		w.setSize(new)
+	}
+}
+
+// Diameter returns the current (interpolated) diameter of the
+// border.
+func (w *worldBorder) Diameter() float64 {
+	if w.lerpDuration <= 0 {
+		return w.newDiameter
+	}
+	progress := float64(time.Since(w.lerpStart)) / float64(w.lerpDuration)
+	if progress >= 1 {
+		w.setSize(w.newDiameter)
+		return w.newDiameter
+	}
+	return w.oldDiameter + (w.newDiameter-w.oldDiameter)*progress
+}
+
+// resizeSpeed returns the number of blocks the border moves per
+// a second.
+func (w *worldBorder) resizeSpeed() float64 {
+	if w.lerpDuration <= 0 {
+		return 0
+	}
+	return math.Abs(w.newDiameter-w.oldDiameter) / w.lerpDuration.Seconds()
+}
+
+// Bounds returns the minimum and maximum x and z coordinates
+// of the border.
+func (w *worldBorder) Bounds() (minX, minZ, maxX, maxZ float64) {
+	radius := w.Diameter() / 2
+	minX = math.Max(w.centerX-radius, -float64(w.portalBoundary))
+	minZ = math.Max(w.centerZ-radius, -float64(w.portalBoundary))
+	maxX = math.Min(w.centerX+radius, float64(w.portalBoundary))
+	maxZ = math.Min(w.centerZ+radius, float64(w.portalBoundary))
+	return
+}
+
+// Inside returns whether the passed position is within the
+// border.
+func (w *worldBorder) Inside(x, z float64) bool {
+	minX, minZ, maxX, maxZ := w.Bounds()
+	return x > minX && x < maxX && z > minZ && z < maxZ
+}
+
+// Distance returns the distance from the passed position to the
+// closest edge of the border.
+func (w *worldBorder) Distance(x, z float64) float64 {
+	minX, minZ, maxX, maxZ := w.Bounds()
+	return math.Min(
+		math.Min(x-minX, maxX-x),
+		math.Min(z-minZ, maxZ-z),
+	)
+}
+
+// collisionBounds returns a set of boxes that cover the area just
+// outside the border.
+func (w *worldBorder) collisionBounds() []vmath.AABB {
+	minX, minZ, maxX, maxZ := w.Bounds()
+	const lowY, highY = -256, 512
+	return []vmath.AABB{
+		{
+			Min: mgl32.Vec3{float32(minX - 1), lowY, float32(minZ - 1)},
+			Max: mgl32.Vec3{float32(minX), highY, float32(maxZ + 1)},
+		},
+		{
+			Min: mgl32.Vec3{float32(maxX), lowY, float32(minZ - 1)},
+			Max: mgl32.Vec3{float32(maxX + 1), highY, float32(maxZ + 1)},
+		},
+		{
+			Min: mgl32.Vec3{float32(minX - 1), lowY, float32(minZ - 1)},
+			Max: mgl32.Vec3{float32(maxX + 1), highY, float32(minZ)},
+		},
+		{
+			Min: mgl32.Vec3{float32(minX - 1), lowY, float32(maxZ)},
+			Max: mgl32.Vec3{float32(maxX + 1), highY, float32(maxZ + 1)},
+		},
+	}
+}
+
+// warningAmount returns how much the screen should be tinted
+// (between 0 and 1) for a player at the passed position.
+func (w *worldBorder) warningAmount(x, z float64) float64 {
+	dist := w.Distance(x, z)
+	moving := math.Min(
+		w.resizeSpeed()*float64(w.warningTime),
+		math.Abs(w.newDiameter-w.Diameter()),
+	)
+	warnDist := math.Max(float64(w.warningBlocks), moving)
+	if dist >= warnDist {
+		return 0
+	}
+	return 1 - dist/warnDist
+}
+
+func (w *worldBorder) tick(x, y, z float64) {
+	width, height := window.GetFramebufferSize()
+	w.tint.SetWidth(float64(width) / ui.Scale)
+	w.tint.SetHeight(float64(height) / ui.Scale)
+
+	warn := w.warningAmount(x, z)
+	w.tint.SetDraw(warn > 0)
+	w.tint.SetA(int(warn * 100))
+
+	dist := w.Distance(x, z)
+	if dist > borderViewDistance {
+		if w.model != nil {
+			w.model.Free()
+			w.model = nil
+		}
+		return
+	}
+	if w.model == nil {
+		w.genModel()
+	}
+
+	// Vanilla's colours for a shrinking, growing and stable
+	// border
+	r, g, b := float32(0x20)/255, float32(0xA0)/255, float32(0xFF)/255
+	if current := w.Diameter(); w.newDiameter < current {
+		r, g, b = float32(0xFF)/255, float32(0x30)/255, float32(0x30)/255
+	} else if w.newDiameter > current {
+		r, g, b = float32(0x40)/255, float32(0xFF)/255, float32(0x80)/255
+	}
+	alpha := float32(math.Pow(1-dist/borderViewDistance, 4))
+
+	// The wall moves with the player in steps of the texture's
+	// size so that it appears fixed in place. The scroll is done
+	// by moving the wall instead of the texture.
+	scroll := float64(time.Now().UnixNano()/int64(time.Millisecond)%3000) / 3000 * borderTextureScale
+	snap := func(v float64) float32 {
+		return float32(math.Floor(v/borderTextureScale)*borderTextureScale + scroll)
+	}
+	sy := -snap(y)
+
+	minX, minZ, maxX, maxZ := w.Bounds()
+	w.model.Matrix[0] = mgl32.Translate3D(float32(minX), sy, snap(z))
+	w.model.Matrix[1] = mgl32.Translate3D(float32(maxX), sy, snap(z))
+	w.model.Matrix[2] = mgl32.Translate3D(snap(x), sy, float32(minZ)).
+		Mul4(mgl32.HomogRotate3DY(math.Pi / 2))
+	w.model.Matrix[3] = mgl32.Translate3D(snap(x), sy, float32(maxZ)).
+		Mul4(mgl32.HomogRotate3DY(math.Pi / 2))
+	for i := range w.model.Colors {
+		w.model.Colors[i] = [4]float32{r, g, b, alpha}
+	}
+}
+
+// genModel creates the wall model. The model has a part for
+// each side of the border which are each a flat plane along
+// the z axis centered on 0,0,0.
+func (w *worldBorder) genModel() {
+	tex := render.GetTexture("misc/forcefield")
+	const (
+		half = borderSegmentSize * borderSegmentCount / 2
+		tw   = borderSegmentSize / borderTextureScale
+	)
+	var verts []*render.ModelVertex
+	for sy := 0; sy < borderSegmentCount; sy++ {
+		for sz := 0; sz < borderSegmentCount; sz++ {
+			y1 := float32(sy*borderSegmentSize - half)
+			y2 := y1 + borderSegmentSize
+			z1 := float32(sz*borderSegmentSize - half)
+			z2 := z1 + borderSegmentSize
+			// Drawn twice so that it can be seen from both sides
+			verts = append(verts,
+				&render.ModelVertex{X: 0, Y: y1, Z: z1, TextureX: 0, TextureY: tw, Texture: tex, R: 255, G: 255, B: 255, A: 255},
+				&render.ModelVertex{X: 0, Y: y2, Z: z1, TextureX: 0, TextureY: 0, Texture: tex, R: 255, G: 255, B: 255, A: 255},
+				&render.ModelVertex{X: 0, Y: y1, Z: z2, TextureX: tw, TextureY: tw, Texture: tex, R: 255, G: 255, B: 255, A: 255},
+				&render.ModelVertex{X: 0, Y: y2, Z: z2, TextureX: tw, TextureY: 0, Texture: tex, R: 255, G: 255, B: 255, A: 255},
+
+				&render.ModelVertex{X: 0, Y: y1, Z: z2, TextureX: tw, TextureY: tw, Texture: tex, R: 255, G: 255, B: 255, A: 255},
+				&render.ModelVertex{X: 0, Y: y2, Z: z2, TextureX: tw, TextureY: 0, Texture: tex, R: 255, G: 255, B: 255, A: 255},
+				&render.ModelVertex{X: 0, Y: y1, Z: z1, TextureX: 0, TextureY: tw, Texture: tex, R: 255, G: 255, B: 255, A: 255},
+				&render.ModelVertex{X: 0, Y: y2, Z: z1, TextureX: 0, TextureY: 0, Texture: tex, R: 255, G: 255, B: 255, A: 255},
+			)
+		}
+	}
+	parts := make([][]*render.ModelVertex, 4)
+	for i := range parts {
+		parts[i] = make([]*render.ModelVertex, len(verts))
+		for j, v := range verts {
+			vv := *v
+			parts[i][j] = &vv
+		}
+	}
+	w.model = render.NewModelCollection(parts, render.SunModels)
+}
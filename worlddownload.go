@@ -0,0 +1,136 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/thinkofdeath/steven/console"
+	"github.com/thinkofdeath/steven/encoding/nbt"
+	"github.com/thinkofdeath/steven/format"
+	"github.com/thinkofdeath/steven/world"
+	"github.com/thinkofdeath/steven/world/anvil"
+)
+
+// worldsDir is where saved worlds are placed.
+const worldsDir = "worlds"
+
+func init() {
+	console.Register("save_world %", saveWorld)
+}
+
+// savingWorld is set whilst a world is being written to disk.
+// The block entity nbt is shared with the writer so only one
+// save can run at a time.
+var savingWorld bool
+
+// saveWorld saves the chunks that are currently loaded into a
+// world that can be opened in Minecraft. Saving into an existing
+// world replaces the chunks that are loaded and keeps the rest.
+//
+// The chunks are copied on the main goroutine and written to disk
+// in the background.
+func saveWorld(name string) {
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		console.Text("Invalid world name %q", name)
+		return
+	}
+	if savingWorld {
+		console.Text("A world is already being saved")
+		return
+	}
+	if len(chunkMap.chunks) == 0 {
+		console.Text("No chunks are loaded")
+		return
+	}
+	dir := filepath.Join(worldsDir, name)
+
+	chunks := map[world.ChunkPosition]*nbt.Compound{}
+	for pos, c := range chunkMap.chunks {
+		var tags []*nbt.Compound
+		for bp, tag := range c.blockEntityTags {
+			// Always use our position in case the server
+			// left it out
+			tag.Items["x"] = int32(bp.X)
+			tag.Items["y"] = int32(bp.Y)
+			tag.Items["z"] = int32(bp.Z)
+			tags = append(tags, tag)
+		}
+		chunks[world.ChunkPosition{X: pos.X, Z: pos.Z}] = anvil.EncodeChunk(c.data, saveCombinedID, tags)
+	}
+	level := anvil.Level{
+		Name:       name,
+		SpawnX:     Client.SpawnPosition.X,
+		SpawnY:     Client.SpawnPosition.Y,
+		SpawnZ:     Client.SpawnPosition.Z,
+		GameMode:   int(Client.GameMode),
+		Hardcore:   Client.HardCore,
+		Difficulty: int(Client.Difficulty),
+		DayTime:    int64(Client.WorldTime),
+	}
+
+	savingWorld = true
+	console.Text("Saving %d chunks to %s", len(chunks), dir)
+	go func() {
+		err := anvil.SaveChunks(dir, chunks)
+		if err == nil {
+			err = anvil.WriteLevel(dir, level)
+		}
+		syncChan <- func() {
+			savingWorld = false
+			if err != nil {
+				console.Text("Failed to save the world: %s", err)
+				return
+			}
+			console.Text("Saved %d chunks to %s", len(chunks), dir)
+		}
+	}()
+}
+
+// saveCombinedID returns the block id and data of the block to
+// save. States that only exist on the client (e.g. fence
+// connections) are reset before saving, Minecraft works them out
+// again itself.
+func saveCombinedID(state uint16) uint16 {
+	b := allBlocks[state]
+	bs := b.BlockSet()
+	if bs == Blocks.MissingBlock {
+		return 0
+	}
+	data := b.toSaveData()
+	if data == -1 {
+		// The block's rules don't cover this state
+		data = 0
+	}
+	return uint16(bs.ID<<4 | data)
+}
+
+// signTag creates the nbt Minecraft stores for a sign with the
+// passed lines.
+func signTag(lines [4]format.AnyComponent) *nbt.Compound {
+	tag := nbt.NewCompound()
+	tag.Items["id"] = "Sign"
+	for i := range lines {
+		text, err := json.Marshal(&lines[i])
+		if err != nil {
+			text = []byte(`""`)
+		}
+		tag.Items[fmt.Sprintf("Text%d", i+1)] = string(text)
+	}
+	return tag
+}
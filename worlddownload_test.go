@@ -0,0 +1,67 @@
+// Copyright 2015 Matthew Collins
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steven
+
+import (
+	"fmt"
+	"testing"
+)
+
+// The states that Minecraft saves which must survive a save and
+// load unless the client works them out itself.
+var persistedStates = []string{"facing", "half", "color", "delay"}
+
+func TestSaveCombinedID(t *testing.T) {
+	if len(allBlocks) == 0 {
+		flattenBlocks()
+	}
+	for _, b := range allBlocks {
+		bs := b.BlockSet()
+		if bs == Blocks.MissingBlock {
+			continue
+		}
+		if b.toSaveData() == -1 {
+			t.Errorf("%s has no data value to save", b)
+			continue
+		}
+		id := saveCombinedID(b.SID())
+		loaded := GetBlockByCombinedID(id)
+		if !loaded.Is(bs) {
+			t.Errorf("%s saved as %d:%d loads as %s", b, id>>4, id&0xF, loaded)
+			continue
+		}
+		for _, key := range persistedStates {
+			if bs.clientStates[key] {
+				continue
+			}
+			want, ok := blockStateString(b, key)
+			if !ok {
+				continue
+			}
+			if got, _ := blockStateString(loaded, key); got != want {
+				t.Errorf("%s saved as %d:%d loads with %s=%s", b, id>>4, id&0xF, key, got)
+			}
+		}
+	}
+}
+
+func blockStateString(b Block, key string) (string, bool) {
+	for _, s := range b.states() {
+		if s.Key == key {
+			return fmt.Sprint(s.Value), true
+		}
+	}
+	return "", false
+}